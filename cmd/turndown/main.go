@@ -1,6 +1,7 @@
 package main
 
 import (
+	"context"
 	"flag"
 	"fmt"
 	"net/http"
@@ -8,9 +9,11 @@ import (
 	"path/filepath"
 	"time"
 
+	"k8s.io/client-go/dynamic"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/rest"
 	"k8s.io/client-go/tools/clientcmd"
+	"k8s.io/client-go/tools/record"
 
 	clientset "github.com/kubecost/cluster-turndown/pkg/generated/clientset/versioned"
 	informers "github.com/kubecost/cluster-turndown/pkg/generated/informers/externalversions"
@@ -23,20 +26,91 @@ import (
 	"k8s.io/klog"
 )
 
-// Run web server with turndown endpoints
-func runWebServer(kubeClient kubernetes.Interface, client clientset.Interface, scheduler *turndown.TurndownScheduler, manager turndown.TurndownManager, provider provider.ComputeProvider) {
+const (
+	// Time allowed for in-flight requests to complete once shutting down
+	ShutdownTimeout = 20 * time.Second
+)
+
+// WebServerOptions configures authentication and TLS for the turndown endpoints
+type WebServerOptions struct {
+	EnableAuth  bool
+	TLSCertFile string
+	TLSKeyFile  string
+}
+
+// Run web server with turndown endpoints until stopCh is closed
+func runWebServer(kubeClient kubernetes.Interface, client clientset.Interface, scheduler *turndown.TurndownScheduler, manager turndown.TurndownManager, provider provider.ComputeProvider, opts WebServerOptions, stopCh <-chan struct{}) {
 	mux := http.NewServeMux()
 
 	endpoints := turndown.NewTurndownEndpoints(kubeClient, client, scheduler, manager, provider)
+	auth := turndown.NewAuthenticator(kubeClient, opts.EnableAuth)
+
+	mux.HandleFunc("/schedule", auth.Authorize(turndown.MethodVerbs{http.MethodGet: "get", http.MethodPost: "create"}, endpoints.HandleStartSchedule))
+	mux.HandleFunc("/cancel", auth.Authorize(turndown.MethodVerbs{turndown.AnyMethod: "delete"}, endpoints.HandleCancelSchedule))
+	mux.HandleFunc("/snooze", auth.Authorize(turndown.MethodVerbs{http.MethodPost: "update"}, endpoints.HandleSnooze))
+	mux.HandleFunc("/scaledown", auth.Authorize(turndown.MethodVerbs{http.MethodPost: "update"}, endpoints.HandleScaleDown))
+	mux.HandleFunc("/scaleup", auth.Authorize(turndown.MethodVerbs{http.MethodPost: "update"}, endpoints.HandleScaleUp))
+
+	// Versioned API, along with the OpenAPI document describing it
+	routes := endpoints.V1Routes()
+	for _, route := range routes {
+		mux.HandleFunc(route.Pattern, auth.Authorize(route.Verbs(), route.ServeHTTP))
+	}
+	mux.HandleFunc(turndown.OpenAPIPath, turndown.OpenAPIHandler(routes))
+
+	server := &http.Server{Addr: ":9731", Handler: mux}
+
+	if opts.TLSCertFile != "" && opts.TLSKeyFile != "" {
+		klog.V(1).Infof("Serving turndown endpoints with TLS")
+		serveUntil(server, stopCh, func() error {
+			return server.ListenAndServeTLS(opts.TLSCertFile, opts.TLSKeyFile)
+		})
+		return
+	}
+
+	serveUntil(server, stopCh, server.ListenAndServe)
+}
 
-	mux.HandleFunc("/schedule", endpoints.HandleStartSchedule)
-	mux.HandleFunc("/cancel", endpoints.HandleCancelSchedule)
+// Serves the waking page for ingress backends redirected while the cluster is scaled down
+func runWakeProxy(wakeWatcher *turndown.WakeWatcher, stopCh <-chan struct{}) {
+	server := &http.Server{Addr: fmt.Sprintf(":%d", turndown.WakeProxyPort), Handler: wakeWatcher.Proxy()}
+	serveUntil(server, stopCh, server.ListenAndServe)
+}
 
-	klog.Fatal(http.ListenAndServe(":9731", mux))
+// Serves the TurndownSchedule validating and defaulting admission webhook with TLS
+func runAdmissionWebhook(certFile string, keyFile string, stopCh <-chan struct{}) {
+	server := &http.Server{Addr: fmt.Sprintf(":%d", turndown.WebhookPort), Handler: turndown.NewAdmissionWebhook().Handler()}
+	serveUntil(server, stopCh, func() error {
+		return server.ListenAndServeTLS(certFile, keyFile)
+	})
 }
 
-// Initialize Kubernetes Client as well as the CRD Client
-func initKubernetes(isLocal bool) (kubernetes.Interface, clientset.Interface, error) {
+// Runs the server until stopCh is closed, then waits for in-flight requests to drain
+func serveUntil(server *http.Server, stopCh <-chan struct{}, listen func() error) {
+	drained := make(chan struct{})
+	go func() {
+		defer close(drained)
+
+		<-stopCh
+		ctx, cancel := context.WithTimeout(context.Background(), ShutdownTimeout)
+		defer cancel()
+
+		err := server.Shutdown(ctx)
+		if err != nil {
+			klog.V(1).Infof("Failed to drain requests on %s: %s", server.Addr, err.Error())
+		}
+	}()
+
+	err := listen()
+	if err != http.ErrServerClosed {
+		klog.Fatal(err)
+	}
+
+	<-drained
+}
+
+// Initialize Kubernetes Client, the CRD Client, and the Dynamic Client
+func initKubernetes(isLocal bool) (kubernetes.Interface, clientset.Interface, dynamic.Interface, error) {
 	var kc *rest.Config
 	var err error
 
@@ -44,7 +118,7 @@ func initKubernetes(isLocal bool) (kubernetes.Interface, clientset.Interface, er
 	if isLocal {
 		homeDir, err := os.UserHomeDir()
 		if err != nil {
-			return nil, nil, err
+			return nil, nil, nil, err
 		}
 
 		configFile := filepath.Join(homeDir, ".kube", "config")
@@ -52,33 +126,38 @@ func initKubernetes(isLocal bool) (kubernetes.Interface, clientset.Interface, er
 
 		kc, err = clientcmd.BuildConfigFromFlags("", configFile)
 		if err != nil {
-			return nil, nil, err
+			return nil, nil, nil, err
 		}
 	} else {
 		kc, err = rest.InClusterConfig()
 		if err != nil {
-			return nil, nil, err
+			return nil, nil, nil, err
 		}
 	}
 
 	kubeClient, err := kubernetes.NewForConfig(kc)
 	if err != nil {
-		return nil, nil, err
+		return nil, nil, nil, err
 	}
 
 	client, err := clientset.NewForConfig(kc)
 	if err != nil {
-		return nil, nil, err
+		return nil, nil, nil, err
+	}
+
+	dynamicClient, err := dynamic.NewForConfig(kc)
+	if err != nil {
+		return nil, nil, nil, err
 	}
 
-	return kubeClient, client, nil
+	return kubeClient, client, dynamicClient, nil
 }
 
 // Runs a controller loop to ensure that our custom resource definition: TurndownSchedule is handled properly
 // by the API.
-func runTurndownResourceController(kubeClient kubernetes.Interface, tdClient clientset.Interface, scheduler *turndown.TurndownScheduler, stopCh <-chan struct{}) {
+func runTurndownResourceController(kubeClient kubernetes.Interface, tdClient clientset.Interface, scheduler *turndown.TurndownScheduler, recorder record.EventRecorder, stopCh <-chan struct{}) {
 	tdInformer := informers.NewSharedInformerFactory(tdClient, time.Second*30)
-	controller := turndown.NewTurndownScheduleResourceController(kubeClient, tdClient, scheduler, tdInformer.Kubecost().V1alpha1().TurndownSchedules())
+	controller := turndown.NewTurndownScheduleResourceController(kubeClient, tdClient, scheduler, tdInformer.Kubecost().V1alpha1().TurndownSchedules(), recorder)
 	tdInformer.Start(stopCh)
 
 	go func(c *turndown.TurndownScheduleResourceController, s <-chan struct{}) {
@@ -96,6 +175,12 @@ func strategyForProvider(c kubernetes.Interface, p provider.ComputeProvider) (st
 		return strategy.NewMasterlessTurndownStrategy(c, p), nil
 	case *provider.AWSProvider:
 		return strategy.NewStandardTurndownStrategy(c, p), nil
+	case *provider.DOProvider:
+		return strategy.NewMasterlessTurndownStrategy(c, p), nil
+	case *provider.CAPIProvider:
+		return strategy.NewStandardTurndownStrategy(c, p), nil
+	case *provider.NoopProvider:
+		return strategy.NewNoopTurndownStrategy(), nil
 	default:
 		return nil, fmt.Errorf("No strategy available for: %+v", v)
 	}
@@ -104,6 +189,16 @@ func strategyForProvider(c kubernetes.Interface, p provider.ComputeProvider) (st
 func main() {
 	klog.InitFlags(nil)
 	flag.Set("v", "3")
+
+	workloadOnly := flag.Bool("workload-only", false, "Only flatten and suspend workloads during turndown, without resizing any node pools.")
+	enableAuth := flag.Bool("enable-auth", true, "Require a bearer token authorized for turndownschedules on the turndown endpoints. Set --enable-auth=false to allow unauthenticated requests.")
+	tlsCertFile := flag.String("tls-cert-file", "", "Certificate file used to serve the turndown endpoints with TLS.")
+	tlsKeyFile := flag.String("tls-key-file", "", "Private key file used to serve the turndown endpoints with TLS.")
+	leaderElect := flag.Bool("leader-elect", false, "Elect a leader using a Lease so only one replica runs turndown schedules.")
+	webhookCertFile := flag.String("webhook-cert-file", "", "Certificate file used to serve the TurndownSchedule admission webhook. The webhook is disabled if unset.")
+	webhookKeyFile := flag.String("webhook-key-file", "", "Private key file used to serve the TurndownSchedule admission webhook.")
+	runRetention := flag.Duration("run-retention", turndown.DefaultRunRetention, "How long completed TurndownRun resources are kept. Set to 0 to keep runs regardless of age.")
+	runHistoryLimit := flag.Int("run-history-limit", turndown.DefaultRunHistoryLimit, "Maximum number of completed TurndownRun resources kept per schedule. Set to 0 for no limit.")
 	flag.Parse()
 
 	stopCh := signals.SetupSignalHandler()
@@ -112,7 +207,7 @@ func main() {
 	klog.V(1).Infof("Running Kubecost Turndown on: %s", node)
 
 	// Setup Components
-	kubeClient, tdClient, err := initKubernetes(false)
+	kubeClient, tdClient, dynamicClient, err := initKubernetes(false)
 	if err != nil {
 		klog.Fatalf("Failed to initialize kubernetes client: %s", err.Error())
 	}
@@ -121,18 +216,29 @@ func main() {
 	scheduleStore := turndown.NewKubernetesScheduleStore(tdClient)
 	//scheduleStore := turndown.NewDiskScheduleStore("/var/configs/schedule.json")
 
+	// Each scale down and scale up is recorded as a TurndownRun resource
+	runStore := turndown.NewKubernetesRunStore(tdClient)
+
 	// Platform Provider for Turndown API
-	computeProvider, err := provider.NewProvider(kubeClient)
-	if err != nil {
-		klog.V(1).Infof("[Error]: Failed to determine provider: %s", err.Error())
-		return
+	var computeProvider provider.ComputeProvider
+	if *workloadOnly {
+		klog.V(1).Infof("Workload-only mode enabled. Node pools will not be resized.")
+		computeProvider = provider.NewNoopProvider()
+	} else {
+		computeProvider, err = provider.NewProvider(kubeClient, dynamicClient)
+		if err != nil {
+			klog.V(1).Infof("[Error]: Failed to determine provider: %s", err.Error())
+			return
+		}
 	}
 
-	// Validate ComputeProvider
-	err = provider.Validate(computeProvider, 5)
-	if err != nil {
-		klog.V(1).Infof("[Error]: Failed to validate provider: %s", err.Error())
-		return
+	// Validate ComputeProvider -- workload-only turndown does not have any node pools
+	if !provider.IsWorkloadOnly(computeProvider) {
+		err = provider.Validate(computeProvider, 5)
+		if err != nil {
+			klog.V(1).Infof("[Error]: Failed to validate provider: %s", err.Error())
+			return
+		}
 	}
 
 	// Determine the best turndown strategy to use based on provider
@@ -143,12 +249,53 @@ func main() {
 	}
 
 	// Turndown Management and Scheduler
-	manager := turndown.NewKubernetesTurndownManager(kubeClient, computeProvider, strategy, node)
-	scheduler := turndown.NewTurndownScheduler(manager, scheduleStore)
+	// Events for each turndown step are recorded on the TurndownSchedule, nodes and workloads
+	recorder := turndown.NewEventRecorder(kubeClient)
 
-	// Run TurndownSchedule Kubernetes Resource Controller
-	runTurndownResourceController(kubeClient, tdClient, scheduler, stopCh)
+	manager := turndown.NewKubernetesTurndownManager(kubeClient, dynamicClient, computeProvider, strategy, node, stopCh, recorder)
+
+	// Scheduling and turndown components only run on the leader when leader election is enabled
+	run := func(stopCh <-chan struct{}) {
+		// TurndownRun Pruner closes the runs left open by a previous pod, so it is created before the
+		// scheduler can start any runs
+		pruner := turndown.NewTurndownRunPruner(tdClient, *runRetention, *runHistoryLimit)
+
+		// Scheduler restores the current schedule from the store
+		scheduler := turndown.NewTurndownScheduler(manager, scheduleStore, runStore, recorder)
+
+		// Run TurndownSchedule Kubernetes Resource Controller
+		runTurndownResourceController(kubeClient, tdClient, scheduler, recorder, stopCh)
+
+		// Run TurndownRun Pruner to apply the run retention
+		pruner.Run(stopCh)
+
+		// Run Idle Monitor for schedules with an idle policy
+		turndown.NewIdleMonitor(kubeClient, tdClient, dynamicClient, scheduler).Run(stopCh)
+
+		// Run Wake Watcher for schedules with a wake policy
+		wakeWatcher := turndown.NewWakeWatcher(kubeClient, tdClient, scheduler)
+		wakeWatcher.Run(stopCh)
+		go runWakeProxy(wakeWatcher, stopCh)
+
+		// Run Turndown Endpoints until shutdown, then wait for the running job to complete or checkpoint
+		runWebServer(kubeClient, tdClient, scheduler, manager, computeProvider, WebServerOptions{
+			EnableAuth:  *enableAuth,
+			TLSCertFile: *tlsCertFile,
+			TLSKeyFile:  *tlsKeyFile,
+		}, stopCh)
+		scheduler.Shutdown()
+	}
+
+	// Admission webhook is served by every replica, as it does not depend on the schedule
+	if *webhookCertFile != "" && *webhookKeyFile != "" {
+		klog.V(1).Infof("Serving TurndownSchedule admission webhook on port %d", turndown.WebhookPort)
+		go runAdmissionWebhook(*webhookCertFile, *webhookKeyFile, stopCh)
+	}
+
+	if *leaderElect {
+		turndown.RunLeaderElection(kubeClient, manager, run, stopCh)
+		return
+	}
 
-	// Run Turndown Endpoints
-	runWebServer(kubeClient, tdClient, scheduler, manager, computeProvider)
+	run(stopCh)
 }
@@ -5,7 +5,7 @@ DIR=`pwd`
 PREFIX=github.com
 PACKAGE=${PREFIX}/kubecost/cluster-turndown
 API=turndownschedule
-VERSION=v1alpha1
+VERSIONS="v1alpha1 v1alpha2"
 
 # Cleanup after generation
 post_clean() {
@@ -22,9 +22,11 @@ clean_generated() {
     if [ -d "./pkg/generated" ]; then
         rm -rf ./pkg/generated 
     fi
-    if [ -f "./pkg/apis/${API}/${VERSION}/zz_generated.deepcopy.go" ]; then
-        rm -f ./pkg/apis/${API}/${VERSION}/zz_generated.deepcopy.go
-    fi
+    for VERSION in ${VERSIONS}; do
+        if [ -f "./pkg/apis/${API}/${VERSION}/zz_generated.deepcopy.go" ]; then
+            rm -f ./pkg/apis/${API}/${VERSION}/zz_generated.deepcopy.go
+        fi
+    done
     post_clean
 }
 
@@ -63,11 +65,13 @@ CODEGEN_PKG=${CODEGEN_PKG:-$(cd "${SCRIPT_ROOT}"; ls -d -1 ./vendor/k8s.io/code-
 bash ${CODEGEN_PKG}/generate-groups.sh all \
     ${PACKAGE}/pkg/generated \
     ${PACKAGE}/pkg/apis \
-    turndownschedule:${VERSION} \
+    turndownschedule:${VERSIONS// /,} \
     --output-base "$(dirname "${BASH_SOURCE[0]}")" \
     --go-header-file hack/custom-boilerplate.go.txt
 
 mv ./${PACKAGE}/pkg/generated ./pkg/generated
-mv ./${PACKAGE}/pkg/apis/${API}/${VERSION}/* ./pkg/apis/${API}/${VERSION}/
+for VERSION in ${VERSIONS}; do
+    mv ./${PACKAGE}/pkg/apis/${API}/${VERSION}/* ./pkg/apis/${API}/${VERSION}/
+done
 
 post_clean
@@ -6,10 +6,12 @@ require (
 	cloud.google.com/go v0.46.3
 	cloud.google.com/go/storage v1.1.0 // indirect
 	github.com/aws/aws-sdk-go v1.28.7
+	github.com/evanphx/json-patch v4.2.0+incompatible
 	github.com/google/uuid v1.1.1
 	github.com/googleapis/gax-go/v2 v2.0.5
 	github.com/imdario/mergo v0.3.8 // indirect
 	github.com/prometheus/common v0.7.0 // indirect
+	google.golang.org/api v0.9.0
 	google.golang.org/genproto v0.0.0-20190911173649-1774047e7e51
 	k8s.io/api v0.0.0-20190913080256-21721929cffa
 	k8s.io/apimachinery v0.0.0-20190913075812-e119e5e154b6
@@ -1,7 +1,9 @@
 package v1alpha1
 
 import (
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
 )
 
 // +genclient
@@ -17,24 +19,95 @@ type TurndownSchedule struct {
 	Status TurndownScheduleStatus `json:"status"`
 }
 
-// TurndownScheduleSpec is the spec for a TurndownSchedule resource
+// TurndownScheduleSpec is the spec for a TurndownSchedule resource. If Action is set to scaledown, the
+// cluster is scaled down immediately instead of at Start, and stays down until scaled up on demand.
 type TurndownScheduleSpec struct {
-	Start  metav1.Time `json:"start"`
-	End    metav1.Time `json:"end"`
-	Repeat string      `json:"repeat"`
+	Start           metav1.Time      `json:"start"`
+	End             metav1.Time      `json:"end"`
+	Repeat          string           `json:"repeat"`
+	Action          string           `json:"action,omitempty"`
+	NodePoolTargets []NodePoolTarget `json:"nodePoolTargets,omitempty"`
+	IdlePolicy      *IdlePolicy      `json:"idlePolicy,omitempty"`
+	WakePolicy      *WakePolicy      `json:"wakePolicy,omitempty"`
+	SnoozePolicy    *SnoozePolicy    `json:"snoozePolicy,omitempty"`
+}
+
+// NodePoolTarget is the size to scale a node pool down to, either an absolute node count or a
+// percentage of the current node count. Node pools without a target are scaled down to 0.
+type NodePoolTarget struct {
+	NodePool string             `json:"nodePool"`
+	Size     intstr.IntOrString `json:"size"`
+}
+
+// IdlePolicy triggers a scale down when the cluster utilization stays below the CPU and memory thresholds
+// for the entire window. Utilization is measured as a percentage of node allocatable, using pod requests,
+// or metrics-server usage if UseMetrics is set. A threshold of 0 is ignored.
+type IdlePolicy struct {
+	CPUThreshold       int32           `json:"cpuThreshold,omitempty"`
+	MemoryThreshold    int32           `json:"memoryThreshold,omitempty"`
+	Window             metav1.Duration `json:"window"`
+	UseMetrics         bool            `json:"useMetrics,omitempty"`
+	ExcludedNamespaces []string        `json:"excludedNamespaces,omitempty"`
+}
+
+// WakePolicy scales the cluster up early when new pods are pending in namespaces labeled with
+// kubecost.kubernetes.io/turndown-wake=true while the cluster is scaled down. If StayUp is set, the
+// cluster is scaled back down once it has elapsed, unless the next scheduled scale up comes first. If
+// HTTP is set, the services behind ingresses in the labeled namespaces are redirected to a page served by
+// turndown, which wakes the cluster, until their workloads are ready again.
+type WakePolicy struct {
+	StayUp metav1.Duration `json:"stayUp,omitempty"`
+	HTTP   bool            `json:"http,omitempty"`
+}
+
+// SnoozePolicy limits how far the next scale down can be postponed from its scheduled time
+type SnoozePolicy struct {
+	MaxDuration metav1.Duration `json:"maxDuration"`
 }
 
 // TurndownScheduleStatus is the status for a TurndownSchedule resource
 type TurndownScheduleStatus struct {
-	State             string            `json:"state"`
-	LastUpdated       metav1.Time       `json:"lastUpdated"`
-	Current           string            `json:"current,omitempty"`
-	ScaleDownID       string            `json:"scaleDownId,omitempty"`
-	ScaleDownTime     metav1.Time       `json:"nextScaleDownTime,omitempty"`
-	ScaleDownMetadata map[string]string `json:"scaleDownMetadata,omitempty"`
-	ScaleUpID         string            `json:"scaleUpID,omitempty"`
-	ScaleUpTime       metav1.Time       `json:"nextScaleUpTime,omitempty"`
-	ScaleUpMetadata   map[string]string `json:"scaleUpMetadata,omitempty"`
+	State              string                      `json:"state"`
+	LastUpdated        metav1.Time                 `json:"lastUpdated"`
+	Current            string                      `json:"current,omitempty"`
+	ScaleDownID        string                      `json:"scaleDownId,omitempty"`
+	ScaleDownTime      metav1.Time                 `json:"nextScaleDownTime,omitempty"`
+	ScaleDownMetadata  map[string]string           `json:"scaleDownMetadata,omitempty"`
+	ScaleUpID          string                      `json:"scaleUpID,omitempty"`
+	ScaleUpTime        metav1.Time                 `json:"nextScaleUpTime,omitempty"`
+	ScaleUpMetadata    map[string]string           `json:"scaleUpMetadata,omitempty"`
+	ScaledDownPools    []string                    `json:"scaledDownPools,omitempty"`
+	ScaleDownReason    string                      `json:"scaleDownReason,omitempty"`
+	WakeUntil          metav1.Time                 `json:"wakeUntil,omitempty"`
+	SnoozedBy          string                      `json:"snoozedBy,omitempty"`
+	SnoozeReason       string                      `json:"snoozeReason,omitempty"`
+	InterruptedJob     string                      `json:"interruptedJob,omitempty"`
+	CompletedSteps     []string                    `json:"completedSteps,omitempty"`
+	ObservedGeneration int64                       `json:"observedGeneration,omitempty"`
+	Conditions         []TurndownScheduleCondition `json:"conditions,omitempty"`
+	History            []TurndownRunSummary        `json:"history,omitempty"`
+}
+
+// TurndownScheduleCondition describes an aspect of the state of a TurndownSchedule, ie: whether or not
+// the cluster is scaled down
+type TurndownScheduleCondition struct {
+	Type               string                 `json:"type"`
+	Status             corev1.ConditionStatus `json:"status"`
+	LastTransitionTime metav1.Time            `json:"lastTransitionTime,omitempty"`
+	Reason             string                 `json:"reason,omitempty"`
+	Message            string                 `json:"message,omitempty"`
+}
+
+// TurndownRunSummary is a past scale down or scale up run by a TurndownSchedule. Outcome is one of
+// Succeeded, Failed or Interrupted.
+type TurndownRunSummary struct {
+	Type     string          `json:"type"`
+	Start    metav1.Time     `json:"start"`
+	End      metav1.Time     `json:"end"`
+	Duration metav1.Duration `json:"duration"`
+	Outcome  string          `json:"outcome"`
+	Pools    []string        `json:"pools,omitempty"`
+	Error    string          `json:"error,omitempty"`
 }
 
 // +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
@@ -46,3 +119,69 @@ type TurndownScheduleList struct {
 
 	Items []TurndownSchedule `json:"items"`
 }
+
+// +genclient
+// +genclient:nonNamespaced
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// TurndownRun is a record of a single scale down or scale up run by a TurndownSchedule, which is labeled
+// with the name of the TurndownSchedule
+type TurndownRun struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   TurndownRunSpec   `json:"spec"`
+	Status TurndownRunStatus `json:"status"`
+}
+
+// TurndownRunSpec is the spec for a TurndownRun resource. Type is either scaledown or scaleup, and Reason
+// is set for scale downs triggered outside of the schedule, ie: idle.
+type TurndownRunSpec struct {
+	Schedule string          `json:"schedule"`
+	Type     string          `json:"type"`
+	Reason   string          `json:"reason,omitempty"`
+	Plan     TurndownRunPlan `json:"plan"`
+}
+
+// TurndownRunPlan is what the run set out to do: the node pool targets of a scale down, or the node pools
+// restored by a scale up. ResumedSteps are the steps completed by a previous interrupted run, which are
+// skipped.
+type TurndownRunPlan struct {
+	NodePoolTargets []NodePoolTarget `json:"nodePoolTargets,omitempty"`
+	NodePools       []string         `json:"nodePools,omitempty"`
+	ResumedSteps    []string         `json:"resumedSteps,omitempty"`
+}
+
+// TurndownRunStatus is the status for a TurndownRun resource. Phase is one of Running, Succeeded, Failed
+// or Interrupted.
+type TurndownRunStatus struct {
+	Phase     string               `json:"phase"`
+	Start     metav1.Time          `json:"start"`
+	End       metav1.Time          `json:"end,omitempty"`
+	Duration  metav1.Duration      `json:"duration,omitempty"`
+	Steps     []TurndownStepResult `json:"steps,omitempty"`
+	Pools     []string             `json:"pools,omitempty"`
+	Nodes     []string             `json:"nodes,omitempty"`
+	Workloads []string             `json:"workloads,omitempty"`
+	Error     string               `json:"error,omitempty"`
+}
+
+// TurndownStepResult is the result of a single step of a TurndownRun. Outcome is either Succeeded or
+// Failed.
+type TurndownStepResult struct {
+	Name    string      `json:"name"`
+	Start   metav1.Time `json:"start"`
+	End     metav1.Time `json:"end"`
+	Outcome string      `json:"outcome"`
+	Error   string      `json:"error,omitempty"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// TurndownRunList is a list of TurndownRun resources
+type TurndownRunList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata"`
+
+	Items []TurndownRun `json:"items"`
+}
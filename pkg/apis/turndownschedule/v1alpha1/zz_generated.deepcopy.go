@@ -9,6 +9,236 @@ import (
 	runtime "k8s.io/apimachinery/pkg/runtime"
 )
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *IdlePolicy) DeepCopyInto(out *IdlePolicy) {
+	*out = *in
+	out.Window = in.Window
+	if in.ExcludedNamespaces != nil {
+		in, out := &in.ExcludedNamespaces, &out.ExcludedNamespaces
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IdlePolicy.
+func (in *IdlePolicy) DeepCopy() *IdlePolicy {
+	if in == nil {
+		return nil
+	}
+	out := new(IdlePolicy)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NodePoolTarget) DeepCopyInto(out *NodePoolTarget) {
+	*out = *in
+	out.Size = in.Size
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NodePoolTarget.
+func (in *NodePoolTarget) DeepCopy() *NodePoolTarget {
+	if in == nil {
+		return nil
+	}
+	out := new(NodePoolTarget)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SnoozePolicy) DeepCopyInto(out *SnoozePolicy) {
+	*out = *in
+	out.MaxDuration = in.MaxDuration
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SnoozePolicy.
+func (in *SnoozePolicy) DeepCopy() *SnoozePolicy {
+	if in == nil {
+		return nil
+	}
+	out := new(SnoozePolicy)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TurndownRun) DeepCopyInto(out *TurndownRun) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TurndownRun.
+func (in *TurndownRun) DeepCopy() *TurndownRun {
+	if in == nil {
+		return nil
+	}
+	out := new(TurndownRun)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *TurndownRun) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TurndownRunList) DeepCopyInto(out *TurndownRunList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]TurndownRun, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TurndownRunList.
+func (in *TurndownRunList) DeepCopy() *TurndownRunList {
+	if in == nil {
+		return nil
+	}
+	out := new(TurndownRunList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *TurndownRunList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TurndownRunPlan) DeepCopyInto(out *TurndownRunPlan) {
+	*out = *in
+	if in.NodePoolTargets != nil {
+		in, out := &in.NodePoolTargets, &out.NodePoolTargets
+		*out = make([]NodePoolTarget, len(*in))
+		copy(*out, *in)
+	}
+	if in.NodePools != nil {
+		in, out := &in.NodePools, &out.NodePools
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.ResumedSteps != nil {
+		in, out := &in.ResumedSteps, &out.ResumedSteps
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TurndownRunPlan.
+func (in *TurndownRunPlan) DeepCopy() *TurndownRunPlan {
+	if in == nil {
+		return nil
+	}
+	out := new(TurndownRunPlan)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TurndownRunSpec) DeepCopyInto(out *TurndownRunSpec) {
+	*out = *in
+	in.Plan.DeepCopyInto(&out.Plan)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TurndownRunSpec.
+func (in *TurndownRunSpec) DeepCopy() *TurndownRunSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(TurndownRunSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TurndownRunStatus) DeepCopyInto(out *TurndownRunStatus) {
+	*out = *in
+	in.Start.DeepCopyInto(&out.Start)
+	in.End.DeepCopyInto(&out.End)
+	out.Duration = in.Duration
+	if in.Steps != nil {
+		in, out := &in.Steps, &out.Steps
+		*out = make([]TurndownStepResult, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Pools != nil {
+		in, out := &in.Pools, &out.Pools
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Nodes != nil {
+		in, out := &in.Nodes, &out.Nodes
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Workloads != nil {
+		in, out := &in.Workloads, &out.Workloads
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TurndownRunStatus.
+func (in *TurndownRunStatus) DeepCopy() *TurndownRunStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(TurndownRunStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TurndownRunSummary) DeepCopyInto(out *TurndownRunSummary) {
+	*out = *in
+	in.Start.DeepCopyInto(&out.Start)
+	in.End.DeepCopyInto(&out.End)
+	out.Duration = in.Duration
+	if in.Pools != nil {
+		in, out := &in.Pools, &out.Pools
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TurndownRunSummary.
+func (in *TurndownRunSummary) DeepCopy() *TurndownRunSummary {
+	if in == nil {
+		return nil
+	}
+	out := new(TurndownRunSummary)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *TurndownSchedule) DeepCopyInto(out *TurndownSchedule) {
 	*out = *in
@@ -37,6 +267,23 @@ func (in *TurndownSchedule) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TurndownScheduleCondition) DeepCopyInto(out *TurndownScheduleCondition) {
+	*out = *in
+	in.LastTransitionTime.DeepCopyInto(&out.LastTransitionTime)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TurndownScheduleCondition.
+func (in *TurndownScheduleCondition) DeepCopy() *TurndownScheduleCondition {
+	if in == nil {
+		return nil
+	}
+	out := new(TurndownScheduleCondition)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *TurndownScheduleList) DeepCopyInto(out *TurndownScheduleList) {
 	*out = *in
@@ -75,6 +322,26 @@ func (in *TurndownScheduleSpec) DeepCopyInto(out *TurndownScheduleSpec) {
 	*out = *in
 	in.Start.DeepCopyInto(&out.Start)
 	in.End.DeepCopyInto(&out.End)
+	if in.NodePoolTargets != nil {
+		in, out := &in.NodePoolTargets, &out.NodePoolTargets
+		*out = make([]NodePoolTarget, len(*in))
+		copy(*out, *in)
+	}
+	if in.IdlePolicy != nil {
+		in, out := &in.IdlePolicy, &out.IdlePolicy
+		*out = new(IdlePolicy)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.WakePolicy != nil {
+		in, out := &in.WakePolicy, &out.WakePolicy
+		*out = new(WakePolicy)
+		**out = **in
+	}
+	if in.SnoozePolicy != nil {
+		in, out := &in.SnoozePolicy, &out.SnoozePolicy
+		*out = new(SnoozePolicy)
+		**out = **in
+	}
 	return
 }
 
@@ -108,6 +375,31 @@ func (in *TurndownScheduleStatus) DeepCopyInto(out *TurndownScheduleStatus) {
 			(*out)[key] = val
 		}
 	}
+	if in.ScaledDownPools != nil {
+		in, out := &in.ScaledDownPools, &out.ScaledDownPools
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	in.WakeUntil.DeepCopyInto(&out.WakeUntil)
+	if in.CompletedSteps != nil {
+		in, out := &in.CompletedSteps, &out.CompletedSteps
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]TurndownScheduleCondition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.History != nil {
+		in, out := &in.History, &out.History
+		*out = make([]TurndownRunSummary, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 	return
 }
 
@@ -120,3 +412,38 @@ func (in *TurndownScheduleStatus) DeepCopy() *TurndownScheduleStatus {
 	in.DeepCopyInto(out)
 	return out
 }
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TurndownStepResult) DeepCopyInto(out *TurndownStepResult) {
+	*out = *in
+	in.Start.DeepCopyInto(&out.Start)
+	in.End.DeepCopyInto(&out.End)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TurndownStepResult.
+func (in *TurndownStepResult) DeepCopy() *TurndownStepResult {
+	if in == nil {
+		return nil
+	}
+	out := new(TurndownStepResult)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *WakePolicy) DeepCopyInto(out *WakePolicy) {
+	*out = *in
+	out.StayUp = in.StayUp
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new WakePolicy.
+func (in *WakePolicy) DeepCopy() *WakePolicy {
+	if in == nil {
+		return nil
+	}
+	out := new(WakePolicy)
+	in.DeepCopyInto(out)
+	return out
+}
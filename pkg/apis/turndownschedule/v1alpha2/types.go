@@ -0,0 +1,167 @@
+package v1alpha2
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+// TurndownAction is a scale down or scale up of the cluster
+type TurndownAction string
+
+const (
+	ActionScaleDown TurndownAction = "ScaleDown"
+	ActionScaleUp   TurndownAction = "ScaleUp"
+)
+
+// RepeatFrequency is how often a schedule repeats
+type RepeatFrequency string
+
+const (
+	RepeatNone   RepeatFrequency = "None"
+	RepeatDaily  RepeatFrequency = "Daily"
+	RepeatWeekly RepeatFrequency = "Weekly"
+)
+
+// +genclient
+// +genclient:nonNamespaced
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// TurndownSchedule is a specification for a TurndownSchedule resource
+type TurndownSchedule struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   TurndownScheduleSpec   `json:"spec"`
+	Status TurndownScheduleStatus `json:"status"`
+}
+
+// TurndownScheduleSpec is the spec for a TurndownSchedule resource. If Action is set to ScaleDown, the
+// cluster is scaled down immediately instead of at Start, and stays down until scaled up on demand.
+type TurndownScheduleSpec struct {
+	Start    metav1.Time    `json:"start"`
+	End      metav1.Time    `json:"end"`
+	Repeat   RepeatRule     `json:"repeat,omitempty"`
+	Action   TurndownAction `json:"action,omitempty"`
+	Scope    ScopeSelector  `json:"scope,omitempty"`
+	Policies Policies       `json:"policies,omitempty"`
+}
+
+// RepeatRule determines how the scale down and scale up repeat. A schedule without a frequency runs once.
+type RepeatRule struct {
+	Frequency RepeatFrequency `json:"frequency,omitempty"`
+}
+
+// ScopeSelector selects the node pools affected by the schedule, and the size each is scaled down to.
+// Node pools without a target are scaled down to 0.
+type ScopeSelector struct {
+	NodePools []NodePoolTarget `json:"nodePools,omitempty"`
+}
+
+// NodePoolTarget is the size to scale a node pool down to, either an absolute node count or a
+// percentage of the current node count.
+type NodePoolTarget struct {
+	NodePool string             `json:"nodePool"`
+	Size     intstr.IntOrString `json:"size"`
+}
+
+// Policies which trigger or postpone a scale down or scale up outside of the schedule
+type Policies struct {
+	Idle   *IdlePolicy   `json:"idle,omitempty"`
+	Wake   *WakePolicy   `json:"wake,omitempty"`
+	Snooze *SnoozePolicy `json:"snooze,omitempty"`
+}
+
+// IdlePolicy triggers a scale down when the cluster utilization stays below the CPU and memory thresholds
+// for the entire window. Utilization is measured as a percentage of node allocatable, using pod requests,
+// or metrics-server usage if UseMetrics is set. A threshold of 0 is ignored.
+type IdlePolicy struct {
+	CPUThreshold       int32           `json:"cpuThreshold,omitempty"`
+	MemoryThreshold    int32           `json:"memoryThreshold,omitempty"`
+	Window             metav1.Duration `json:"window"`
+	UseMetrics         bool            `json:"useMetrics,omitempty"`
+	ExcludedNamespaces []string        `json:"excludedNamespaces,omitempty"`
+}
+
+// WakePolicy scales the cluster up early when new pods are pending in namespaces labeled with
+// kubecost.kubernetes.io/turndown-wake=true while the cluster is scaled down. If StayUp is set, the
+// cluster is scaled back down once it has elapsed. If HTTP is set, requests to ingresses in the labeled
+// namespaces also wake the cluster.
+type WakePolicy struct {
+	StayUp metav1.Duration `json:"stayUp,omitempty"`
+	HTTP   bool            `json:"http,omitempty"`
+}
+
+// SnoozePolicy limits how far the next scale down can be postponed from its scheduled time
+type SnoozePolicy struct {
+	MaxDuration metav1.Duration `json:"maxDuration"`
+}
+
+// TurndownScheduleStatus is the status for a TurndownSchedule resource
+type TurndownScheduleStatus struct {
+	State              string                      `json:"state"`
+	ObservedGeneration int64                       `json:"observedGeneration,omitempty"`
+	LastUpdated        metav1.Time                 `json:"lastUpdated"`
+	Current            TurndownAction              `json:"current,omitempty"`
+	ScaleDown          *ScheduledJob               `json:"scaleDown,omitempty"`
+	ScaleUp            *ScheduledJob               `json:"scaleUp,omitempty"`
+	ScaledDownPools    []string                    `json:"scaledDownPools,omitempty"`
+	ScaleDownReason    string                      `json:"scaleDownReason,omitempty"`
+	WakeUntil          metav1.Time                 `json:"wakeUntil,omitempty"`
+	Snooze             *SnoozeStatus               `json:"snooze,omitempty"`
+	Checkpoint         *Checkpoint                 `json:"checkpoint,omitempty"`
+	Conditions         []TurndownScheduleCondition `json:"conditions,omitempty"`
+	History            []TurndownRunSummary        `json:"history,omitempty"`
+}
+
+// ScheduledJob is the next scale down or scale up scheduled by turndown. Cadence is the original time
+// of a snoozed scale down, which the schedule repeats from.
+type ScheduledJob struct {
+	ID      string       `json:"id,omitempty"`
+	Time    metav1.Time  `json:"time"`
+	Cadence *metav1.Time `json:"cadence,omitempty"`
+}
+
+// SnoozeStatus describes who postponed the next scale down, and why
+type SnoozeStatus struct {
+	By     string `json:"by,omitempty"`
+	Reason string `json:"reason,omitempty"`
+}
+
+// Checkpoint is a scale down or scale up interrupted by a shutdown, along with the steps it completed
+type Checkpoint struct {
+	Action         TurndownAction `json:"action"`
+	CompletedSteps []string       `json:"completedSteps,omitempty"`
+}
+
+// TurndownScheduleCondition describes an aspect of the state of a TurndownSchedule, ie: whether or not
+// the cluster is scaled down
+type TurndownScheduleCondition struct {
+	Type               string                 `json:"type"`
+	Status             corev1.ConditionStatus `json:"status"`
+	LastTransitionTime metav1.Time            `json:"lastTransitionTime,omitempty"`
+	Reason             string                 `json:"reason,omitempty"`
+	Message            string                 `json:"message,omitempty"`
+}
+
+// TurndownRunSummary is a past scale down or scale up run by a TurndownSchedule. Outcome is one of
+// Succeeded, Failed or Interrupted.
+type TurndownRunSummary struct {
+	Action   TurndownAction  `json:"action"`
+	Start    metav1.Time     `json:"start"`
+	End      metav1.Time     `json:"end"`
+	Duration metav1.Duration `json:"duration"`
+	Outcome  string          `json:"outcome"`
+	Pools    []string        `json:"pools,omitempty"`
+	Error    string          `json:"error,omitempty"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// TurndownScheduleList is a list of TurndownSchedule resources
+type TurndownScheduleList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata"`
+
+	Items []TurndownSchedule `json:"items"`
+}
@@ -7,6 +7,7 @@ import (
 	"fmt"
 
 	kubecostv1alpha1 "github.com/kubecost/cluster-turndown/pkg/generated/clientset/versioned/typed/turndownschedule/v1alpha1"
+	kubecostv1alpha2 "github.com/kubecost/cluster-turndown/pkg/generated/clientset/versioned/typed/turndownschedule/v1alpha2"
 	discovery "k8s.io/client-go/discovery"
 	rest "k8s.io/client-go/rest"
 	flowcontrol "k8s.io/client-go/util/flowcontrol"
@@ -15,6 +16,7 @@ import (
 type Interface interface {
 	Discovery() discovery.DiscoveryInterface
 	KubecostV1alpha1() kubecostv1alpha1.KubecostV1alpha1Interface
+	KubecostV1alpha2() kubecostv1alpha2.KubecostV1alpha2Interface
 }
 
 // Clientset contains the clients for groups. Each group has exactly one
@@ -22,6 +24,7 @@ type Interface interface {
 type Clientset struct {
 	*discovery.DiscoveryClient
 	kubecostV1alpha1 *kubecostv1alpha1.KubecostV1alpha1Client
+	kubecostV1alpha2 *kubecostv1alpha2.KubecostV1alpha2Client
 }
 
 // KubecostV1alpha1 retrieves the KubecostV1alpha1Client
@@ -29,6 +32,11 @@ func (c *Clientset) KubecostV1alpha1() kubecostv1alpha1.KubecostV1alpha1Interfac
 	return c.kubecostV1alpha1
 }
 
+// KubecostV1alpha2 retrieves the KubecostV1alpha2Client
+func (c *Clientset) KubecostV1alpha2() kubecostv1alpha2.KubecostV1alpha2Interface {
+	return c.kubecostV1alpha2
+}
+
 // Discovery retrieves the DiscoveryClient
 func (c *Clientset) Discovery() discovery.DiscoveryInterface {
 	if c == nil {
@@ -54,6 +62,10 @@ func NewForConfig(c *rest.Config) (*Clientset, error) {
 	if err != nil {
 		return nil, err
 	}
+	cs.kubecostV1alpha2, err = kubecostv1alpha2.NewForConfig(&configShallowCopy)
+	if err != nil {
+		return nil, err
+	}
 
 	cs.DiscoveryClient, err = discovery.NewDiscoveryClientForConfig(&configShallowCopy)
 	if err != nil {
@@ -67,6 +79,7 @@ func NewForConfig(c *rest.Config) (*Clientset, error) {
 func NewForConfigOrDie(c *rest.Config) *Clientset {
 	var cs Clientset
 	cs.kubecostV1alpha1 = kubecostv1alpha1.NewForConfigOrDie(c)
+	cs.kubecostV1alpha2 = kubecostv1alpha2.NewForConfigOrDie(c)
 
 	cs.DiscoveryClient = discovery.NewDiscoveryClientForConfigOrDie(c)
 	return &cs
@@ -76,6 +89,7 @@ func NewForConfigOrDie(c *rest.Config) *Clientset {
 func New(c rest.Interface) *Clientset {
 	var cs Clientset
 	cs.kubecostV1alpha1 = kubecostv1alpha1.New(c)
+	cs.kubecostV1alpha2 = kubecostv1alpha2.New(c)
 
 	cs.DiscoveryClient = discovery.NewDiscoveryClient(c)
 	return &cs
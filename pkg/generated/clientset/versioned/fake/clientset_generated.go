@@ -7,6 +7,8 @@ import (
 	clientset "github.com/kubecost/cluster-turndown/pkg/generated/clientset/versioned"
 	kubecostv1alpha1 "github.com/kubecost/cluster-turndown/pkg/generated/clientset/versioned/typed/turndownschedule/v1alpha1"
 	fakekubecostv1alpha1 "github.com/kubecost/cluster-turndown/pkg/generated/clientset/versioned/typed/turndownschedule/v1alpha1/fake"
+	kubecostv1alpha2 "github.com/kubecost/cluster-turndown/pkg/generated/clientset/versioned/typed/turndownschedule/v1alpha2"
+	fakekubecostv1alpha2 "github.com/kubecost/cluster-turndown/pkg/generated/clientset/versioned/typed/turndownschedule/v1alpha2/fake"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/watch"
 	"k8s.io/client-go/discovery"
@@ -65,3 +67,8 @@ var _ clientset.Interface = &Clientset{}
 func (c *Clientset) KubecostV1alpha1() kubecostv1alpha1.KubecostV1alpha1Interface {
 	return &fakekubecostv1alpha1.FakeKubecostV1alpha1{Fake: &c.Fake}
 }
+
+// KubecostV1alpha2 retrieves the KubecostV1alpha2Client
+func (c *Clientset) KubecostV1alpha2() kubecostv1alpha2.KubecostV1alpha2Interface {
+	return &fakekubecostv1alpha2.FakeKubecostV1alpha2{Fake: &c.Fake}
+}
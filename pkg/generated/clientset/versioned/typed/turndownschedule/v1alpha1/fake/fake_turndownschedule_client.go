@@ -13,6 +13,10 @@ type FakeKubecostV1alpha1 struct {
 	*testing.Fake
 }
 
+func (c *FakeKubecostV1alpha1) TurndownRuns() v1alpha1.TurndownRunInterface {
+	return &FakeTurndownRuns{c}
+}
+
 func (c *FakeKubecostV1alpha1) TurndownSchedules() v1alpha1.TurndownScheduleInterface {
 	return &FakeTurndownSchedules{c}
 }
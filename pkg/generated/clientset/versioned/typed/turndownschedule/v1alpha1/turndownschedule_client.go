@@ -11,6 +11,7 @@ import (
 
 type KubecostV1alpha1Interface interface {
 	RESTClient() rest.Interface
+	TurndownRunsGetter
 	TurndownSchedulesGetter
 }
 
@@ -19,6 +20,10 @@ type KubecostV1alpha1Client struct {
 	restClient rest.Interface
 }
 
+func (c *KubecostV1alpha1Client) TurndownRuns() TurndownRunInterface {
+	return newTurndownRuns(c)
+}
+
 func (c *KubecostV1alpha1Client) TurndownSchedules() TurndownScheduleInterface {
 	return newTurndownSchedules(c)
 }
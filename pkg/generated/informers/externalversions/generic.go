@@ -7,6 +7,7 @@ import (
 	"fmt"
 
 	v1alpha1 "github.com/kubecost/cluster-turndown/pkg/apis/turndownschedule/v1alpha1"
+	v1alpha2 "github.com/kubecost/cluster-turndown/pkg/apis/turndownschedule/v1alpha2"
 	schema "k8s.io/apimachinery/pkg/runtime/schema"
 	cache "k8s.io/client-go/tools/cache"
 )
@@ -38,9 +39,15 @@ func (f *genericInformer) Lister() cache.GenericLister {
 func (f *sharedInformerFactory) ForResource(resource schema.GroupVersionResource) (GenericInformer, error) {
 	switch resource {
 	// Group=kubecost.k8s.io, Version=v1alpha1
+	case v1alpha1.SchemeGroupVersion.WithResource("turndownruns"):
+		return &genericInformer{resource: resource.GroupResource(), informer: f.Kubecost().V1alpha1().TurndownRuns().Informer()}, nil
 	case v1alpha1.SchemeGroupVersion.WithResource("turndownschedules"):
 		return &genericInformer{resource: resource.GroupResource(), informer: f.Kubecost().V1alpha1().TurndownSchedules().Informer()}, nil
 
+		// Group=kubecost.k8s.io, Version=v1alpha2
+	case v1alpha2.SchemeGroupVersion.WithResource("turndownschedules"):
+		return &genericInformer{resource: resource.GroupResource(), informer: f.Kubecost().V1alpha2().TurndownSchedules().Informer()}, nil
+
 	}
 
 	return nil, fmt.Errorf("no informer found for %v", resource)
@@ -6,12 +6,15 @@ package turndownschedule
 import (
 	internalinterfaces "github.com/kubecost/cluster-turndown/pkg/generated/informers/externalversions/internalinterfaces"
 	v1alpha1 "github.com/kubecost/cluster-turndown/pkg/generated/informers/externalversions/turndownschedule/v1alpha1"
+	v1alpha2 "github.com/kubecost/cluster-turndown/pkg/generated/informers/externalversions/turndownschedule/v1alpha2"
 )
 
 // Interface provides access to each of this group's versions.
 type Interface interface {
 	// V1alpha1 provides access to shared informers for resources in V1alpha1.
 	V1alpha1() v1alpha1.Interface
+	// V1alpha2 provides access to shared informers for resources in V1alpha2.
+	V1alpha2() v1alpha2.Interface
 }
 
 type group struct {
@@ -29,3 +32,8 @@ func New(f internalinterfaces.SharedInformerFactory, namespace string, tweakList
 func (g *group) V1alpha1() v1alpha1.Interface {
 	return v1alpha1.New(g.factory, g.namespace, g.tweakListOptions)
 }
+
+// V1alpha2 returns a new v1alpha2.Interface.
+func (g *group) V1alpha2() v1alpha2.Interface {
+	return v1alpha2.New(g.factory, g.namespace, g.tweakListOptions)
+}
@@ -9,6 +9,8 @@ import (
 
 // Interface provides access to all the informers in this group version.
 type Interface interface {
+	// TurndownRuns returns a TurndownRunInformer.
+	TurndownRuns() TurndownRunInformer
 	// TurndownSchedules returns a TurndownScheduleInformer.
 	TurndownSchedules() TurndownScheduleInformer
 }
@@ -24,6 +26,11 @@ func New(f internalinterfaces.SharedInformerFactory, namespace string, tweakList
 	return &version{factory: f, namespace: namespace, tweakListOptions: tweakListOptions}
 }
 
+// TurndownRuns returns a TurndownRunInformer.
+func (v *version) TurndownRuns() TurndownRunInformer {
+	return &turndownRunInformer{factory: v.factory, tweakListOptions: v.tweakListOptions}
+}
+
 // TurndownSchedules returns a TurndownScheduleInformer.
 func (v *version) TurndownSchedules() TurndownScheduleInformer {
 	return &turndownScheduleInformer{factory: v.factory, tweakListOptions: v.tweakListOptions}
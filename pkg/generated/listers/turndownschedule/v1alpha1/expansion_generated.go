@@ -3,6 +3,10 @@
 
 package v1alpha1
 
+// TurndownRunListerExpansion allows custom methods to be added to
+// TurndownRunLister.
+type TurndownRunListerExpansion interface{}
+
 // TurndownScheduleListerExpansion allows custom methods to be added to
 // TurndownScheduleLister.
 type TurndownScheduleListerExpansion interface{}
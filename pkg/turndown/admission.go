@@ -0,0 +1,221 @@
+package turndown
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"reflect"
+	"strings"
+
+	"github.com/kubecost/cluster-turndown/pkg/apis/turndownschedule/v1alpha1"
+	"github.com/kubecost/cluster-turndown/pkg/logging"
+
+	"k8s.io/api/admission/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// Paths served by the admission webhook
+	ValidatePath = "/validate"
+	MutatePath   = "/mutate"
+
+	WebhookPort = 9733
+)
+
+// JSONPatchOp is a single JSON patch operation returned by the defaulting webhook
+type JSONPatchOp struct {
+	Op    string      `json:"op"`
+	Path  string      `json:"path"`
+	Value interface{} `json:"value,omitempty"`
+}
+
+// AdmissionWebhook validates and defaults TurndownSchedule resources as they are created or updated, so
+// invalid schedules are rejected by kubectl rather than failing once they are scheduled. It also converts
+// TurndownSchedule resources between the served api versions.
+type AdmissionWebhook struct {
+	log logging.NamedLogger
+}
+
+// Creates a new AdmissionWebhook instance
+func NewAdmissionWebhook() *AdmissionWebhook {
+	return &AdmissionWebhook{
+		log: logging.NamedLogger("AdmissionWebhook"),
+	}
+}
+
+// Handler serves the validating, mutating and conversion webhooks
+func (aw *AdmissionWebhook) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc(ValidatePath, aw.serve(aw.validate))
+	mux.HandleFunc(MutatePath, aw.serve(aw.mutate))
+	mux.HandleFunc(ConvertPath, aw.convert)
+	return mux
+}
+
+// Decodes the AdmissionReview request, and writes the response of the admit func
+func (aw *AdmissionWebhook) serve(admit func(*v1beta1.AdmissionRequest) *v1beta1.AdmissionResponse) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+
+		data, err := ioutil.ReadAll(r.Body)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		review := &v1beta1.AdmissionReview{}
+		err = json.Unmarshal(data, review)
+		if err != nil || review.Request == nil {
+			http.Error(w, "Invalid AdmissionReview", http.StatusBadRequest)
+			return
+		}
+
+		response := admit(review.Request)
+		response.UID = review.Request.UID
+
+		review.Response = response
+		review.Request = nil
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(review)
+	}
+}
+
+// Rejects TurndownSchedule resources with an invalid spec
+func (aw *AdmissionWebhook) validate(request *v1beta1.AdmissionRequest) *v1beta1.AdmissionResponse {
+	schedule, old, err := decodeSchedules(request)
+	if err != nil {
+		return deny(err)
+	}
+
+	err = ValidateTurndownSchedule(schedule, old)
+	if err != nil {
+		aw.log.Log("Rejected %s of TurndownSchedule %s: %s", request.Operation, schedule.Name, err.Error())
+		return deny(err)
+	}
+
+	return &v1beta1.AdmissionResponse{Allowed: true}
+}
+
+// Sets defaults on the spec of TurndownSchedule resources
+func (aw *AdmissionWebhook) mutate(request *v1beta1.AdmissionRequest) *v1beta1.AdmissionResponse {
+	schedule, _, err := decodeSchedules(request)
+	if err != nil {
+		return deny(err)
+	}
+
+	ops := defaultTurndownSchedule(schedule)
+	if len(ops) == 0 {
+		return &v1beta1.AdmissionResponse{Allowed: true}
+	}
+
+	patch, err := json.Marshal(ops)
+	if err != nil {
+		return deny(err)
+	}
+
+	patchType := v1beta1.PatchTypeJSONPatch
+	return &v1beta1.AdmissionResponse{
+		Allowed:   true,
+		Patch:     patch,
+		PatchType: &patchType,
+	}
+}
+
+// ValidateTurndownSchedule validates the spec of a created TurndownSchedule, or an updated TurndownSchedule
+// if old is set. Updates which do not change the spec, ie: adding a snooze annotation, are always allowed.
+// Changes to the start, end or repeat of a scheduled turndown are validated the same way as when the
+// schedule is replaced, with the start and end of a repeating schedule projected to their next occurrence.
+func ValidateTurndownSchedule(schedule *v1alpha1.TurndownSchedule, old *v1alpha1.TurndownSchedule) error {
+	if old != nil && reflect.DeepEqual(old.Spec, schedule.Spec) {
+		return nil
+	}
+
+	spec := schedule.Spec
+
+	err := NewNodePoolTargets(spec.NodePoolTargets).Validate()
+	if err != nil {
+		return err
+	}
+
+	if spec.IdlePolicy != nil && spec.IdlePolicy.Window.Duration <= 0 {
+		return fmt.Errorf("The idle policy window must be positive.")
+	}
+
+	if spec.SnoozePolicy != nil && spec.SnoozePolicy.MaxDuration.Duration <= 0 {
+		return fmt.Errorf("The snooze policy max duration must be positive.")
+	}
+
+	switch spec.Action {
+	case "":
+	case TurndownJobTypeScaleDown:
+		return nil
+	default:
+		return fmt.Errorf("Invalid action: %s", spec.Action)
+	}
+
+	repeat := spec.Repeat
+	if old == nil || old.Status.State != ScheduleStateSuccess {
+		return validateSchedule(spec.Start.Time, spec.End.Time, &repeat)
+	}
+
+	scaledDown := old.Status.Current == TurndownJobTypeScaleUp
+	from, to := projectSchedule(spec.Start.Time, spec.End.Time, repeat, scaledDown)
+	return validateReschedule(from, to, &repeat, scaledDown)
+}
+
+// Determines the JSON patch operations which default the spec of a TurndownSchedule
+func defaultTurndownSchedule(schedule *v1alpha1.TurndownSchedule) []JSONPatchOp {
+	var ops []JSONPatchOp
+
+	repeat := schedule.Spec.Repeat
+	if fixupRepeatType(&repeat) != schedule.Spec.Repeat {
+		ops = append(ops, JSONPatchOp{Op: "add", Path: "/spec/repeat", Value: repeat})
+	}
+
+	action := strings.ToLower(schedule.Spec.Action)
+	if action != schedule.Spec.Action {
+		ops = append(ops, JSONPatchOp{Op: "replace", Path: "/spec/action", Value: action})
+	}
+
+	return ops
+}
+
+// Decodes the TurndownSchedule of the admission request, along with the previous TurndownSchedule for
+// updates
+func decodeSchedules(request *v1beta1.AdmissionRequest) (*v1alpha1.TurndownSchedule, *v1alpha1.TurndownSchedule, error) {
+	schedule := &v1alpha1.TurndownSchedule{}
+	err := json.Unmarshal(request.Object.Raw, schedule)
+	if err != nil {
+		return nil, nil, fmt.Errorf("Failed to decode TurndownSchedule: %s", err.Error())
+	}
+
+	if request.Operation != v1beta1.Update || len(request.OldObject.Raw) == 0 {
+		return schedule, nil, nil
+	}
+
+	old := &v1alpha1.TurndownSchedule{}
+	err = json.Unmarshal(request.OldObject.Raw, old)
+	if err != nil {
+		return nil, nil, fmt.Errorf("Failed to decode TurndownSchedule: %s", err.Error())
+	}
+
+	return schedule, old, nil
+}
+
+// Creates a response rejecting the request with the error message
+func deny(err error) *v1beta1.AdmissionResponse {
+	return &v1beta1.AdmissionResponse{
+		Allowed: false,
+		Result: &metav1.Status{
+			Status:  metav1.StatusFailure,
+			Message: err.Error(),
+			Reason:  metav1.StatusReasonInvalid,
+			Code:    http.StatusUnprocessableEntity,
+		},
+	}
+}
@@ -0,0 +1,480 @@
+package turndown
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/kubecost/cluster-turndown/pkg/apis/turndownschedule/v1alpha1"
+
+	jsonpatch "github.com/evanphx/json-patch"
+	"k8s.io/apimachinery/pkg/api/errors"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/klog"
+)
+
+const (
+	APIV1Prefix = "/api/v1"
+)
+
+// RequestError is returned for requests which are malformed or invalid, including schedules, snoozes and
+// node pool targets rejected by the scheduler
+type RequestError struct {
+	error
+}
+
+// ValidationError is returned for well-formed requests to create or update a TurndownSchedule which is
+// rejected by ValidateTurndownSchedule
+type ValidationError struct {
+	error
+}
+
+// ConflictError is returned for requests which are valid, but conflict with the current schedule
+type ConflictError struct {
+	error
+}
+
+// APIOperation describes a single method on an API route, used to authorize requests and generate the
+// OpenAPI document.
+type APIOperation struct {
+	Method   string
+	Verb     string
+	Summary  string
+	Request  interface{}
+	Response interface{}
+	Codes    []int
+}
+
+// APIRoute is a path served by the versioned API along with its operations
+type APIRoute struct {
+	Path       string
+	Pattern    string
+	Operations []APIOperation
+	Handler    http.HandlerFunc
+}
+
+// Verbs returns the turndownschedules verb required for each method on the route
+func (ar APIRoute) Verbs() MethodVerbs {
+	verbs := make(MethodVerbs)
+	for _, op := range ar.Operations {
+		verbs[op.Method] = op.Verb
+	}
+
+	return verbs
+}
+
+// Serves the route, rejecting methods without an operation
+func (ar APIRoute) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	for _, op := range ar.Operations {
+		if op.Method == r.Method {
+			ar.Handler(w, r)
+			return
+		}
+	}
+
+	methods := []string{}
+	for _, op := range ar.Operations {
+		methods = append(methods, op.Method)
+	}
+	sort.Strings(methods)
+
+	w.Header().Set("Allow", strings.Join(methods, ", "))
+	writeV1Error(w, &methodNotAllowedError{r.Method})
+}
+
+type methodNotAllowedError struct {
+	method string
+}
+
+func (e *methodNotAllowedError) Error() string {
+	return fmt.Sprintf("Method Not Allowed: %s", e.method)
+}
+
+// V1Routes returns the routes served under /api/v1
+func (te *TurndownEndpoints) V1Routes() []APIRoute {
+	errorCodes := []int{http.StatusBadRequest, http.StatusUnauthorized, http.StatusForbidden, http.StatusInternalServerError}
+
+	return []APIRoute{
+		{
+			Path:    APIV1Prefix + "/schedules",
+			Pattern: APIV1Prefix + "/schedules",
+			Handler: te.handleV1Schedules,
+			Operations: []APIOperation{
+				{Method: http.MethodGet, Verb: "list", Summary: "List turndown schedules", Response: []v1alpha1.TurndownSchedule{},
+					Codes: append([]int{http.StatusOK}, errorCodes...)},
+				{Method: http.MethodPost, Verb: "create", Summary: "Create a turndown schedule", Request: v1alpha1.TurndownSchedule{}, Response: v1alpha1.TurndownSchedule{},
+					Codes: append([]int{http.StatusCreated, http.StatusConflict, http.StatusUnprocessableEntity}, errorCodes...)},
+			},
+		},
+		{
+			Path:    APIV1Prefix + "/schedules/{name}",
+			Pattern: APIV1Prefix + "/schedules/",
+			Handler: te.handleV1Schedule,
+			Operations: []APIOperation{
+				{Method: http.MethodGet, Verb: "get", Summary: "Get a turndown schedule by name", Response: v1alpha1.TurndownSchedule{},
+					Codes: append([]int{http.StatusOK, http.StatusNotFound}, errorCodes...)},
+				{Method: http.MethodPut, Verb: "update", Summary: "Replace the spec of a turndown schedule", Request: v1alpha1.TurndownSchedule{}, Response: v1alpha1.TurndownSchedule{},
+					Codes: append([]int{http.StatusOK, http.StatusNotFound, http.StatusConflict, http.StatusUnprocessableEntity}, errorCodes...)},
+				{Method: http.MethodPatch, Verb: "patch", Summary: "Apply a JSON merge patch to a turndown schedule", Request: v1alpha1.TurndownSchedule{}, Response: v1alpha1.TurndownSchedule{},
+					Codes: append([]int{http.StatusOK, http.StatusNotFound, http.StatusConflict, http.StatusUnprocessableEntity}, errorCodes...)},
+				{Method: http.MethodDelete, Verb: "delete", Summary: "Delete a turndown schedule, cancelling the turndown",
+					Codes: append([]int{http.StatusAccepted, http.StatusNotFound}, errorCodes...)},
+			},
+		},
+		{
+			Path:    APIV1Prefix + "/scaledown",
+			Pattern: APIV1Prefix + "/scaledown",
+			Handler: te.handleV1ScaleDown,
+			Operations: []APIOperation{
+				{Method: http.MethodPost, Verb: "update", Summary: "Scale down immediately", Request: ScaleDownRequest{}, Response: Schedule{},
+					Codes: append([]int{http.StatusOK, http.StatusCreated, http.StatusConflict}, errorCodes...)},
+			},
+		},
+		{
+			Path:    APIV1Prefix + "/scaleup",
+			Pattern: APIV1Prefix + "/scaleup",
+			Handler: te.handleV1ScaleUp,
+			Operations: []APIOperation{
+				{Method: http.MethodPost, Verb: "update", Summary: "Scale up immediately", Response: Schedule{},
+					Codes: append([]int{http.StatusOK, http.StatusNotFound, http.StatusConflict}, errorCodes...)},
+			},
+		},
+		{
+			Path:    APIV1Prefix + "/snooze",
+			Pattern: APIV1Prefix + "/snooze",
+			Handler: te.handleV1Snooze,
+			Operations: []APIOperation{
+				{Method: http.MethodPost, Verb: "update", Summary: "Postpone the next scale down", Request: SnoozeRequest{}, Response: Schedule{},
+					Codes: append([]int{http.StatusOK, http.StatusNotFound, http.StatusConflict}, errorCodes...)},
+			},
+		},
+		{
+			Path:    APIV1Prefix + "/environment/prepare",
+			Pattern: APIV1Prefix + "/environment/prepare",
+			Handler: te.handleV1PrepareEnvironment,
+			Operations: []APIOperation{
+				{Method: http.MethodPost, Verb: "update", Summary: "Prepare the turndown node and move turndown onto it",
+					Codes: append([]int{http.StatusOK}, errorCodes...)},
+			},
+		},
+		{
+			Path:    APIV1Prefix + "/environment/reset",
+			Pattern: APIV1Prefix + "/environment/reset",
+			Handler: te.handleV1ResetEnvironment,
+			Operations: []APIOperation{
+				{Method: http.MethodPost, Verb: "update", Summary: "Reset the turndown environment after turn up",
+					Codes: append([]int{http.StatusOK}, errorCodes...)},
+			},
+		},
+	}
+}
+
+func (te *TurndownEndpoints) handleV1Schedules(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		list, err := te.client.KubecostV1alpha1().TurndownSchedules().List(v1.ListOptions{})
+		if err != nil {
+			writeV1Error(w, err)
+			return
+		}
+
+		writeV1Response(w, http.StatusOK, list.Items)
+
+	case http.MethodPost:
+		var schedule v1alpha1.TurndownSchedule
+		if err := readV1Request(r, &schedule); err != nil {
+			writeV1Error(w, err)
+			return
+		}
+
+		// Invalid schedules are rejected rather than created in the ScheduleFailed state
+		err := ValidateTurndownSchedule(&schedule, nil)
+		if err != nil {
+			writeV1Error(w, &ValidationError{err})
+			return
+		}
+
+		meta := v1.ObjectMeta{
+			Name:        schedule.Name,
+			Labels:      schedule.Labels,
+			Annotations: schedule.Annotations,
+			Finalizers:  []string{TurndownScheduleFinalizer},
+		}
+		if meta.Name == "" {
+			meta.GenerateName = "scheduled-turndown-"
+		}
+
+		created, err := te.client.KubecostV1alpha1().TurndownSchedules().Create(&v1alpha1.TurndownSchedule{
+			ObjectMeta: meta,
+			Spec:       schedule.Spec,
+		})
+		if err != nil {
+			writeV1Error(w, err)
+			return
+		}
+
+		w.Header().Set("Location", APIV1Prefix+"/schedules/"+created.Name)
+		writeV1Response(w, http.StatusCreated, created)
+	}
+}
+
+func (te *TurndownEndpoints) handleV1Schedule(w http.ResponseWriter, r *http.Request) {
+	name := strings.TrimPrefix(r.URL.Path, APIV1Prefix+"/schedules/")
+	if name == "" || strings.Contains(name, "/") {
+		writeV1Error(w, errors.NewNotFound(v1alpha1.Resource("turndownschedules"), name))
+		return
+	}
+
+	schedules := te.client.KubecostV1alpha1().TurndownSchedules()
+
+	switch r.Method {
+	case http.MethodGet:
+		schedule, err := schedules.Get(name, v1.GetOptions{})
+		if err != nil {
+			writeV1Error(w, err)
+			return
+		}
+
+		writeV1Response(w, http.StatusOK, schedule)
+
+	case http.MethodPut:
+		var request v1alpha1.TurndownSchedule
+		if err := readV1Request(r, &request); err != nil {
+			writeV1Error(w, err)
+			return
+		}
+
+		if request.Name != "" && request.Name != name {
+			writeV1Error(w, &RequestError{fmt.Errorf("Name in body (%s) does not match path (%s)", request.Name, name)})
+			return
+		}
+
+		schedule, err := schedules.Get(name, v1.GetOptions{})
+		if err != nil {
+			writeV1Error(w, err)
+			return
+		}
+
+		old := schedule.DeepCopy()
+		schedule.Spec = request.Spec
+		err = ValidateTurndownSchedule(schedule, old)
+		if err != nil {
+			writeV1Error(w, &ValidationError{err})
+			return
+		}
+
+		updated, err := schedules.Update(schedule)
+		if err != nil {
+			writeV1Error(w, err)
+			return
+		}
+
+		writeV1Response(w, http.StatusOK, updated)
+
+	case http.MethodPatch:
+		data, err := ioutil.ReadAll(r.Body)
+		if err != nil {
+			writeV1Error(w, &RequestError{err})
+			return
+		}
+
+		// The patch is applied locally, so the patched schedule is validated the same way as a replaced
+		// schedule, even without the admission webhook
+		schedule, err := schedules.Get(name, v1.GetOptions{})
+		if err != nil {
+			writeV1Error(w, err)
+			return
+		}
+
+		original, err := json.Marshal(schedule)
+		if err != nil {
+			writeV1Error(w, err)
+			return
+		}
+
+		patchedData, err := jsonpatch.MergePatch(original, data)
+		if err != nil {
+			writeV1Error(w, &RequestError{fmt.Errorf("Invalid merge patch: %s", err.Error())})
+			return
+		}
+
+		var patched v1alpha1.TurndownSchedule
+		err = json.Unmarshal(patchedData, &patched)
+		if err != nil {
+			writeV1Error(w, &RequestError{fmt.Errorf("Invalid merge patch: %s", err.Error())})
+			return
+		}
+
+		if patched.Name != name {
+			writeV1Error(w, &RequestError{fmt.Errorf("Name in patch (%s) does not match path (%s)", patched.Name, name)})
+			return
+		}
+
+		err = ValidateTurndownSchedule(&patched, schedule)
+		if err != nil {
+			writeV1Error(w, &ValidationError{err})
+			return
+		}
+
+		updated, err := schedules.Update(&patched)
+		if err != nil {
+			writeV1Error(w, err)
+			return
+		}
+
+		writeV1Response(w, http.StatusOK, updated)
+
+	case http.MethodDelete:
+		// The finalizer cancels the turndown before the resource is removed
+		err := schedules.Delete(name, &v1.DeleteOptions{})
+		if err != nil {
+			writeV1Error(w, err)
+			return
+		}
+
+		writeV1Response(w, http.StatusAccepted, nil)
+	}
+}
+
+func (te *TurndownEndpoints) handleV1ScaleDown(w http.ResponseWriter, r *http.Request) {
+	var request ScaleDownRequest
+	if err := readV1Request(r, &request); err != nil {
+		writeV1Error(w, err)
+		return
+	}
+
+	schedule, created, err := te.scaleDown(request)
+	if err != nil {
+		writeV1Error(w, err)
+		return
+	}
+
+	if created {
+		writeV1Response(w, http.StatusCreated, schedule)
+		return
+	}
+
+	writeV1Response(w, http.StatusOK, schedule)
+}
+
+func (te *TurndownEndpoints) handleV1ScaleUp(w http.ResponseWriter, r *http.Request) {
+	err := te.scheduler.ScaleUpNow()
+	if err != nil {
+		writeV1Error(w, err)
+		return
+	}
+
+	writeV1Response(w, http.StatusOK, te.scheduler.GetSchedule())
+}
+
+func (te *TurndownEndpoints) handleV1Snooze(w http.ResponseWriter, r *http.Request) {
+	var request SnoozeRequest
+	if err := readV1Request(r, &request); err != nil {
+		writeV1Error(w, err)
+		return
+	}
+
+	schedule, err := te.snooze(request, r)
+	if err != nil {
+		writeV1Error(w, err)
+		return
+	}
+
+	writeV1Response(w, http.StatusOK, schedule)
+}
+
+func (te *TurndownEndpoints) handleV1PrepareEnvironment(w http.ResponseWriter, r *http.Request) {
+	err := te.prepareEnvironment()
+	if err != nil {
+		writeV1Error(w, err)
+		return
+	}
+
+	writeV1Response(w, http.StatusOK, nil)
+}
+
+func (te *TurndownEndpoints) handleV1ResetEnvironment(w http.ResponseWriter, r *http.Request) {
+	err := te.turndown.ResetTurndownEnvironment()
+	if err != nil {
+		writeV1Error(w, err)
+		return
+	}
+
+	writeV1Response(w, http.StatusOK, nil)
+}
+
+// Decodes a JSON request body. An empty body leaves the value unchanged.
+func readV1Request(r *http.Request, v interface{}) error {
+	data, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		return &RequestError{err}
+	}
+
+	if len(data) == 0 {
+		return nil
+	}
+
+	err = json.Unmarshal(data, v)
+	if err != nil {
+		return &RequestError{err}
+	}
+
+	return nil
+}
+
+func writeV1Response(w http.ResponseWriter, code int, data interface{}) {
+	resp, _ := json.Marshal(&DataEnvelope{
+		Code:   code,
+		Status: "success",
+		Data:   data,
+	})
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	w.Write(resp)
+}
+
+func writeV1Error(w http.ResponseWriter, err error) {
+	code := statusCodeFor(err)
+	klog.V(1).Infof("Error returned to client (%d): %s", code, err.Error())
+
+	resp, _ := json.Marshal(&DataEnvelope{
+		Code:   code,
+		Status: "error",
+		Data:   err.Error(),
+	})
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	w.Write(resp)
+}
+
+// Maps errors from the scheduler and Kubernetes API to HTTP status codes
+func statusCodeFor(err error) int {
+	switch e := err.(type) {
+	case *RequestError:
+		return http.StatusBadRequest
+	case *ValidationError:
+		return http.StatusUnprocessableEntity
+	case *ConflictError:
+		return http.StatusConflict
+	case *methodNotAllowedError:
+		return http.StatusMethodNotAllowed
+	case errors.APIStatus:
+		if code := e.Status().Code; code != 0 {
+			return int(code)
+		}
+	}
+
+	switch err {
+	case NoScheduleToTriggerErr, NoScheduleToSnoozeErr, NoSchedulesToCancelErr, NoScheduleToRescheduleErr:
+		return http.StatusNotFound
+	case AlreadyScaledDownErr, AlreadyScaledUpErr, SnoozeWhileRunningErr, CancelWhileRunningErr, RescheduleWhileRunningErr, RescheduleOnDemandErr:
+		return http.StatusConflict
+	}
+
+	return http.StatusInternalServerError
+}
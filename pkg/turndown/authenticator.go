@@ -0,0 +1,180 @@
+package turndown
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/kubecost/cluster-turndown/pkg/apis/turndownschedule/v1alpha1"
+	"github.com/kubecost/cluster-turndown/pkg/logging"
+
+	authnv1 "k8s.io/api/authentication/v1"
+	authzv1 "k8s.io/api/authorization/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+const (
+	// Key used to match any request method when mapping methods to verbs
+	AnyMethod = "*"
+)
+
+type userContextKey struct{}
+
+// MethodVerbs maps request methods to the verb on turndownschedules a user must be allowed to perform
+type MethodVerbs map[string]string
+
+// Returns the value of the Allow header for the mapped methods
+func (mv MethodVerbs) allowed() string {
+	methods := []string{}
+	for method := range mv {
+		if method != AnyMethod {
+			methods = append(methods, method)
+		}
+	}
+	sort.Strings(methods)
+
+	return strings.Join(methods, ", ")
+}
+
+// Authenticator authenticates bearer tokens using the TokenReview API, and authorizes requests using
+// SubjectAccessReviews against verbs on turndownschedules.
+type Authenticator struct {
+	client  kubernetes.Interface
+	enabled bool
+	log     logging.NamedLogger
+}
+
+// Creates a new Authenticator instance. If enabled is false, all requests are allowed.
+func NewAuthenticator(client kubernetes.Interface, enabled bool) *Authenticator {
+	return &Authenticator{
+		client:  client,
+		enabled: enabled,
+		log:     logging.NamedLogger("Authenticator"),
+	}
+}
+
+// Authorize wraps the handler, requiring a bearer token for a user allowed to perform the verb mapped from
+// the request method. Requests with a method that has no verb are rejected with 405. Any origin
+// is only allowed when authentication is disabled, so browsers cannot make requests from other sites with
+// a user's credentials.
+func (a *Authenticator) Authorize(verbs MethodVerbs, handler http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if !a.enabled {
+			w.Header().Set("Access-Control-Allow-Origin", "*")
+			handler(w, r)
+			return
+		}
+
+		// Every request must be authorized, so methods without a verb are not allowed
+		verb, ok := verbs[r.Method]
+		if !ok {
+			verb, ok = verbs[AnyMethod]
+		}
+		if !ok {
+			w.Header().Set("Allow", verbs.allowed())
+			writeAuthError(w, http.StatusMethodNotAllowed, fmt.Errorf("Method %s is not allowed", r.Method))
+			return
+		}
+
+		user, err := a.authenticate(r)
+		if err != nil {
+			a.log.Warn("Authentication failed for %s %s: %s", r.Method, r.URL.Path, err.Error())
+			writeAuthError(w, http.StatusUnauthorized, err)
+			return
+		}
+
+		err = a.authorize(user, verb)
+		if err != nil {
+			a.log.Warn("Authorization failed for %s %s: %s", r.Method, r.URL.Path, err.Error())
+			writeAuthError(w, http.StatusForbidden, err)
+			return
+		}
+
+		handler(w, r.WithContext(context.WithValue(r.Context(), userContextKey{}, user.Username)))
+	}
+}
+
+// UserFromRequest returns the name of the authenticated user for the request, or an empty string if
+// authentication is disabled.
+func UserFromRequest(r *http.Request) string {
+	user, _ := r.Context().Value(userContextKey{}).(string)
+	return user
+}
+
+// Reviews the bearer token of the request and returns the authenticated user
+func (a *Authenticator) authenticate(r *http.Request) (*authnv1.UserInfo, error) {
+	header := r.Header.Get("Authorization")
+	if !strings.HasPrefix(header, "Bearer ") {
+		return nil, fmt.Errorf("Missing bearer token")
+	}
+
+	token := strings.TrimSpace(strings.TrimPrefix(header, "Bearer "))
+	if token == "" {
+		return nil, fmt.Errorf("Missing bearer token")
+	}
+
+	review, err := a.client.AuthenticationV1().TokenReviews().Create(&authnv1.TokenReview{
+		Spec: authnv1.TokenReviewSpec{
+			Token: token,
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if !review.Status.Authenticated {
+		if review.Status.Error != "" {
+			return nil, fmt.Errorf("Token is not authenticated: %s", review.Status.Error)
+		}
+		return nil, fmt.Errorf("Token is not authenticated")
+	}
+
+	return &review.Status.User, nil
+}
+
+// Determines whether the user is allowed to perform the verb on turndownschedules
+func (a *Authenticator) authorize(user *authnv1.UserInfo, verb string) error {
+	extra := make(map[string]authzv1.ExtraValue)
+	for k, v := range user.Extra {
+		extra[k] = authzv1.ExtraValue(v)
+	}
+
+	review, err := a.client.AuthorizationV1().SubjectAccessReviews().Create(&authzv1.SubjectAccessReview{
+		Spec: authzv1.SubjectAccessReviewSpec{
+			ResourceAttributes: &authzv1.ResourceAttributes{
+				Group:    v1alpha1.SchemeGroupVersion.Group,
+				Version:  v1alpha1.SchemeGroupVersion.Version,
+				Resource: "turndownschedules",
+				Verb:     verb,
+			},
+			User:   user.Username,
+			Groups: user.Groups,
+			UID:    user.UID,
+			Extra:  extra,
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	if !review.Status.Allowed {
+		return fmt.Errorf("User %s cannot %s turndownschedules", user.Username, verb)
+	}
+
+	return nil
+}
+
+func writeAuthError(w http.ResponseWriter, code int, err error) {
+	resp, _ := json.Marshal(&DataEnvelope{
+		Code:   code,
+		Status: "error",
+		Data:   err.Error(),
+	})
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	w.Write(resp)
+}
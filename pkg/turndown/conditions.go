@@ -0,0 +1,168 @@
+package turndown
+
+import (
+	"time"
+
+	"github.com/kubecost/cluster-turndown/pkg/apis/turndownschedule/v1alpha1"
+
+	corev1 "k8s.io/api/core/v1"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// Condition types set on the TurndownSchedule status
+	ConditionScheduled  = "Scheduled"
+	ConditionScaledDown = "ScaledDown"
+	ConditionScalingUp  = "ScalingUp"
+	ConditionDegraded   = "Degraded"
+
+	// Condition reasons which are not also schedule states or event reasons
+	ConditionReasonScalingDown    = "ScalingDown"
+	ConditionReasonScaledUp       = "ScaledUp"
+	ConditionReasonScaleUpRunning = "ScaleUpRunning"
+	ConditionReasonNotRunning     = "NotRunning"
+	ConditionReasonHealthy        = "Healthy"
+
+	// Outcomes of a scale down or scale up run
+	RunSucceeded   = "Succeeded"
+	RunFailed      = "Failed"
+	RunInterrupted = "Interrupted"
+
+	// Number of past runs kept in the schedule history
+	MaxRunHistory = 10
+)
+
+// ScheduleRun is a scale down or scale up run by the scheduler
+type ScheduleRun struct {
+	Type    string    `json:"type"`
+	Start   time.Time `json:"start"`
+	End     time.Time `json:"end"`
+	Outcome string    `json:"outcome"`
+	Pools   []string  `json:"pools,omitempty"`
+	Error   string    `json:"error,omitempty"`
+}
+
+// Creates a run with the outcome of a job which started at the provided time. Times are truncated to
+// the second, as they are persisted on the status.
+func newScheduleRun(jobType string, start time.Time, pools []string, err error) *ScheduleRun {
+	run := &ScheduleRun{
+		Type:    jobType,
+		Start:   start.UTC().Truncate(time.Second),
+		End:     time.Now().UTC().Truncate(time.Second),
+		Outcome: RunSucceeded,
+		Pools:   pools,
+	}
+
+	if err == InterruptedErr {
+		run.Outcome = RunInterrupted
+	} else if err != nil {
+		run.Outcome = RunFailed
+		run.Error = err.Error()
+	}
+
+	return run
+}
+
+// Sets the condition on the status. The transition time is only updated if the condition status changes.
+func setCondition(status *v1alpha1.TurndownScheduleStatus, conditionType string, conditionStatus corev1.ConditionStatus, reason string, message string) {
+	condition := v1alpha1.TurndownScheduleCondition{
+		Type:               conditionType,
+		Status:             conditionStatus,
+		LastTransitionTime: v1.NewTime(time.Now().UTC()),
+		Reason:             reason,
+		Message:            message,
+	}
+
+	for i, c := range status.Conditions {
+		if c.Type != conditionType {
+			continue
+		}
+
+		if c.Status == conditionStatus {
+			condition.LastTransitionTime = c.LastTransitionTime
+		}
+		status.Conditions[i] = condition
+		return
+	}
+
+	status.Conditions = append(status.Conditions, condition)
+}
+
+// Updates the conditions on the status of a scheduled turndown from the schedule
+func writeConditions(status *v1alpha1.TurndownScheduleStatus, schedule *Schedule) {
+	setCondition(status, ConditionScheduled, corev1.ConditionTrue, ScheduleStateSuccess, "Turndown is scheduled")
+
+	// A completed scale up leaves the schedule waiting on the scale up until it is rescheduled or completed
+	run := schedule.LastRun
+	scaledUp := run != nil && run.Type == TurndownJobTypeScaleUp && run.Outcome == RunSucceeded
+
+	if schedule.Running == TurndownJobTypeScaleDown {
+		setCondition(status, ConditionScaledDown, corev1.ConditionFalse, ConditionReasonScalingDown, "Cluster is scaling down")
+	} else if schedule.Current == TurndownJobTypeScaleUp && !scaledUp {
+		setCondition(status, ConditionScaledDown, corev1.ConditionTrue, ScaleDownComplete, schedule.ScaleDownReason)
+	} else {
+		setCondition(status, ConditionScaledDown, corev1.ConditionFalse, ConditionReasonScaledUp, "Cluster is scaled up")
+	}
+
+	if schedule.Running == TurndownJobTypeScaleUp {
+		setCondition(status, ConditionScalingUp, corev1.ConditionTrue, ConditionReasonScaleUpRunning, "Cluster is scaling up")
+	} else {
+		setCondition(status, ConditionScalingUp, corev1.ConditionFalse, ConditionReasonNotRunning, "")
+	}
+
+	if run != nil && run.Outcome == RunFailed {
+		reason := ScaleDownFailed
+		if run.Type == TurndownJobTypeScaleUp {
+			reason = ScaleUpFailed
+		}
+		setCondition(status, ConditionDegraded, corev1.ConditionTrue, reason, run.Error)
+	} else {
+		setCondition(status, ConditionDegraded, corev1.ConditionFalse, ConditionReasonHealthy, "")
+	}
+}
+
+// Appends the run to the status history unless it was already recorded, keeping the most recent runs
+func appendHistory(status *v1alpha1.TurndownScheduleStatus, run *ScheduleRun) {
+	if run == nil {
+		return
+	}
+
+	if n := len(status.History); n > 0 {
+		last := status.History[n-1]
+		if last.Type == run.Type && last.Start.Time.Equal(run.Start) {
+			return
+		}
+	}
+
+	status.History = append(status.History, v1alpha1.TurndownRunSummary{
+		Type:     run.Type,
+		Start:    v1.NewTime(run.Start),
+		End:      v1.NewTime(run.End),
+		Duration: v1.Duration{Duration: run.End.Sub(run.Start).Round(time.Second)},
+		Outcome:  run.Outcome,
+		Pools:    run.Pools,
+		Error:    run.Error,
+	})
+
+	if len(status.History) > MaxRunHistory {
+		status.History = status.History[len(status.History)-MaxRunHistory:]
+	}
+}
+
+// Loads the most recent run from the status history
+func lastRunFor(status *v1alpha1.TurndownScheduleStatus) *ScheduleRun {
+	n := len(status.History)
+	if n == 0 {
+		return nil
+	}
+
+	last := status.History[n-1]
+	return &ScheduleRun{
+		Type:    last.Type,
+		Start:   last.Start.Time,
+		End:     last.End.Time,
+		Outcome: last.Outcome,
+		Pools:   last.Pools,
+		Error:   last.Error,
+	}
+}
@@ -0,0 +1,407 @@
+package turndown
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/kubecost/cluster-turndown/pkg/apis/turndownschedule/v1alpha1"
+	"github.com/kubecost/cluster-turndown/pkg/apis/turndownschedule/v1alpha2"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+const (
+	// Path served by the conversion webhook
+	ConvertPath = "/convert"
+)
+
+// ConversionReview is the apiextensions.k8s.io/v1beta1 request and response sent to the conversion webhook
+type ConversionReview struct {
+	metav1.TypeMeta `json:",inline"`
+	Request         *ConversionRequest  `json:"request,omitempty"`
+	Response        *ConversionResponse `json:"response,omitempty"`
+}
+
+// ConversionRequest contains the objects to convert to the desired version
+type ConversionRequest struct {
+	UID               types.UID              `json:"uid"`
+	DesiredAPIVersion string                 `json:"desiredAPIVersion"`
+	Objects           []runtime.RawExtension `json:"objects"`
+}
+
+// ConversionResponse contains the converted objects, in the same order as the request
+type ConversionResponse struct {
+	UID              types.UID              `json:"uid"`
+	ConvertedObjects []runtime.RawExtension `json:"convertedObjects"`
+	Result           metav1.Status          `json:"result"`
+}
+
+// Converts the TurndownSchedule resources in the ConversionReview request to the desired version
+func (aw *AdmissionWebhook) convert(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	data, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	review := &ConversionReview{}
+	err = json.Unmarshal(data, review)
+	if err != nil || review.Request == nil {
+		http.Error(w, "Invalid ConversionReview", http.StatusBadRequest)
+		return
+	}
+
+	response := &ConversionResponse{
+		UID:    review.Request.UID,
+		Result: metav1.Status{Status: metav1.StatusSuccess},
+	}
+
+	for _, obj := range review.Request.Objects {
+		converted, err := ConvertTurndownSchedule(obj.Raw, review.Request.DesiredAPIVersion)
+		if err != nil {
+			aw.log.Err("Failed to convert TurndownSchedule: %s", err.Error())
+
+			response.ConvertedObjects = nil
+			response.Result = metav1.Status{Status: metav1.StatusFailure, Message: err.Error()}
+			break
+		}
+
+		response.ConvertedObjects = append(response.ConvertedObjects, runtime.RawExtension{Raw: converted})
+	}
+
+	review.Response = response
+	review.Request = nil
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(review)
+}
+
+// ConvertTurndownSchedule converts an encoded v1alpha1 or v1alpha2 TurndownSchedule to the desired api version
+func ConvertTurndownSchedule(data []byte, desiredAPIVersion string) ([]byte, error) {
+	typeMeta := &metav1.TypeMeta{}
+	err := json.Unmarshal(data, typeMeta)
+	if err != nil {
+		return nil, err
+	}
+
+	if typeMeta.APIVersion == desiredAPIVersion {
+		return data, nil
+	}
+
+	v1 := v1alpha1.SchemeGroupVersion.String()
+	v2 := v1alpha2.SchemeGroupVersion.String()
+
+	switch {
+	case typeMeta.APIVersion == v1 && desiredAPIVersion == v2:
+		in := &v1alpha1.TurndownSchedule{}
+		err = json.Unmarshal(data, in)
+		if err != nil {
+			return nil, err
+		}
+
+		return json.Marshal(ConvertToV1alpha2(in))
+
+	case typeMeta.APIVersion == v2 && desiredAPIVersion == v1:
+		in := &v1alpha2.TurndownSchedule{}
+		err = json.Unmarshal(data, in)
+		if err != nil {
+			return nil, err
+		}
+
+		return json.Marshal(ConvertToV1alpha1(in))
+	}
+
+	return nil, fmt.Errorf("Unsupported conversion from %s to %s", typeMeta.APIVersion, desiredAPIVersion)
+}
+
+// ConvertToV1alpha2 converts a v1alpha1 TurndownSchedule to v1alpha2. The job metadata maps are replaced
+// by the spec and typed scheduled jobs.
+func ConvertToV1alpha2(in *v1alpha1.TurndownSchedule) *v1alpha2.TurndownSchedule {
+	out := &v1alpha2.TurndownSchedule{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: v1alpha2.SchemeGroupVersion.String(),
+			Kind:       in.Kind,
+		},
+		ObjectMeta: *in.ObjectMeta.DeepCopy(),
+	}
+
+	spec := &in.Spec
+	out.Spec = v1alpha2.TurndownScheduleSpec{
+		Start:  spec.Start,
+		End:    spec.End,
+		Repeat: v1alpha2.RepeatRule{Frequency: toRepeatFrequency(spec.Repeat)},
+		Action: toTurndownAction(spec.Action),
+	}
+
+	for _, t := range spec.NodePoolTargets {
+		out.Spec.Scope.NodePools = append(out.Spec.Scope.NodePools, v1alpha2.NodePoolTarget{NodePool: t.NodePool, Size: t.Size})
+	}
+
+	if p := spec.IdlePolicy; p != nil {
+		out.Spec.Policies.Idle = &v1alpha2.IdlePolicy{
+			CPUThreshold:       p.CPUThreshold,
+			MemoryThreshold:    p.MemoryThreshold,
+			Window:             p.Window,
+			UseMetrics:         p.UseMetrics,
+			ExcludedNamespaces: append([]string(nil), p.ExcludedNamespaces...),
+		}
+	}
+	if p := spec.WakePolicy; p != nil {
+		out.Spec.Policies.Wake = &v1alpha2.WakePolicy{StayUp: p.StayUp, HTTP: p.HTTP}
+	}
+	if p := spec.SnoozePolicy; p != nil {
+		out.Spec.Policies.Snooze = &v1alpha2.SnoozePolicy{MaxDuration: p.MaxDuration}
+	}
+
+	status := &in.Status
+	out.Status = v1alpha2.TurndownScheduleStatus{
+		State:              status.State,
+		ObservedGeneration: status.ObservedGeneration,
+		LastUpdated:        status.LastUpdated,
+		Current:            toTurndownAction(status.Current),
+		ScaleDown:          toScheduledJob(status.ScaleDownID, status.ScaleDownTime, status.ScaleDownMetadata),
+		ScaleUp:            toScheduledJob(status.ScaleUpID, status.ScaleUpTime, status.ScaleUpMetadata),
+		ScaledDownPools:    append([]string(nil), status.ScaledDownPools...),
+		ScaleDownReason:    status.ScaleDownReason,
+		WakeUntil:          status.WakeUntil,
+	}
+
+	if status.SnoozedBy != "" || status.SnoozeReason != "" {
+		out.Status.Snooze = &v1alpha2.SnoozeStatus{By: status.SnoozedBy, Reason: status.SnoozeReason}
+	}
+
+	if status.InterruptedJob != "" {
+		out.Status.Checkpoint = &v1alpha2.Checkpoint{
+			Action:         toTurndownAction(status.InterruptedJob),
+			CompletedSteps: append([]string(nil), status.CompletedSteps...),
+		}
+	}
+
+	for _, c := range status.Conditions {
+		out.Status.Conditions = append(out.Status.Conditions, v1alpha2.TurndownScheduleCondition{
+			Type:               c.Type,
+			Status:             c.Status,
+			LastTransitionTime: c.LastTransitionTime,
+			Reason:             c.Reason,
+			Message:            c.Message,
+		})
+	}
+
+	for _, run := range status.History {
+		out.Status.History = append(out.Status.History, v1alpha2.TurndownRunSummary{
+			Action:   toTurndownAction(run.Type),
+			Start:    run.Start,
+			End:      run.End,
+			Duration: run.Duration,
+			Outcome:  run.Outcome,
+			Pools:    append([]string(nil), run.Pools...),
+			Error:    run.Error,
+		})
+	}
+
+	return out
+}
+
+// ConvertToV1alpha1 converts a v1alpha2 TurndownSchedule to v1alpha1. The job metadata maps are rebuilt
+// from the spec, the same way the scheduler creates them.
+func ConvertToV1alpha1(in *v1alpha2.TurndownSchedule) *v1alpha1.TurndownSchedule {
+	out := &v1alpha1.TurndownSchedule{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: v1alpha1.SchemeGroupVersion.String(),
+			Kind:       in.Kind,
+		},
+		ObjectMeta: *in.ObjectMeta.DeepCopy(),
+	}
+
+	spec := &in.Spec
+	out.Spec = v1alpha1.TurndownScheduleSpec{
+		Start:  spec.Start,
+		End:    spec.End,
+		Repeat: fromRepeatFrequency(spec.Repeat.Frequency),
+		Action: fromTurndownAction(spec.Action),
+	}
+
+	for _, t := range spec.Scope.NodePools {
+		out.Spec.NodePoolTargets = append(out.Spec.NodePoolTargets, v1alpha1.NodePoolTarget{NodePool: t.NodePool, Size: t.Size})
+	}
+
+	if p := spec.Policies.Idle; p != nil {
+		out.Spec.IdlePolicy = &v1alpha1.IdlePolicy{
+			CPUThreshold:       p.CPUThreshold,
+			MemoryThreshold:    p.MemoryThreshold,
+			Window:             p.Window,
+			UseMetrics:         p.UseMetrics,
+			ExcludedNamespaces: append([]string(nil), p.ExcludedNamespaces...),
+		}
+	}
+	if p := spec.Policies.Wake; p != nil {
+		out.Spec.WakePolicy = &v1alpha1.WakePolicy{StayUp: p.StayUp, HTTP: p.HTTP}
+	}
+	if p := spec.Policies.Snooze; p != nil {
+		out.Spec.SnoozePolicy = &v1alpha1.SnoozePolicy{MaxDuration: p.MaxDuration}
+	}
+
+	// On demand schedules run once, regardless of the repeat
+	repeat := out.Spec.Repeat
+	fixupRepeatType(&repeat)
+	if out.Spec.Action != "" {
+		repeat = TurndownJobRepeatNone
+	}
+
+	status := &in.Status
+	out.Status = v1alpha1.TurndownScheduleStatus{
+		State:              status.State,
+		ObservedGeneration: status.ObservedGeneration,
+		LastUpdated:        status.LastUpdated,
+		Current:            fromTurndownAction(status.Current),
+		ScaledDownPools:    append([]string(nil), status.ScaledDownPools...),
+		ScaleDownReason:    status.ScaleDownReason,
+		WakeUntil:          status.WakeUntil,
+	}
+
+	if job := status.ScaleDown; job != nil {
+		out.Status.ScaleDownID = job.ID
+		out.Status.ScaleDownTime = job.Time
+		out.Status.ScaleDownMetadata = fromScheduledJob(job, TurndownJobTypeScaleDown, repeat)
+
+		targets := NewNodePoolTargets(out.Spec.NodePoolTargets)
+		if len(targets) > 0 {
+			out.Status.ScaleDownMetadata[TurndownJobTargets] = targets.String()
+		}
+	}
+
+	if job := status.ScaleUp; job != nil {
+		out.Status.ScaleUpID = job.ID
+		out.Status.ScaleUpTime = job.Time
+		out.Status.ScaleUpMetadata = fromScheduledJob(job, TurndownJobTypeScaleUp, repeat)
+	}
+
+	if snooze := status.Snooze; snooze != nil {
+		out.Status.SnoozedBy = snooze.By
+		out.Status.SnoozeReason = snooze.Reason
+	}
+
+	if checkpoint := status.Checkpoint; checkpoint != nil {
+		out.Status.InterruptedJob = fromTurndownAction(checkpoint.Action)
+		out.Status.CompletedSteps = append([]string(nil), checkpoint.CompletedSteps...)
+	}
+
+	for _, c := range status.Conditions {
+		out.Status.Conditions = append(out.Status.Conditions, v1alpha1.TurndownScheduleCondition{
+			Type:               c.Type,
+			Status:             c.Status,
+			LastTransitionTime: c.LastTransitionTime,
+			Reason:             c.Reason,
+			Message:            c.Message,
+		})
+	}
+
+	for _, run := range status.History {
+		out.Status.History = append(out.Status.History, v1alpha1.TurndownRunSummary{
+			Type:     fromTurndownAction(run.Action),
+			Start:    run.Start,
+			End:      run.End,
+			Duration: run.Duration,
+			Outcome:  run.Outcome,
+			Pools:    append([]string(nil), run.Pools...),
+			Error:    run.Error,
+		})
+	}
+
+	return out
+}
+
+// Creates the scheduled job from the v1alpha1 job status. Returns nil if the job was not scheduled.
+func toScheduledJob(id string, t metav1.Time, metadata map[string]string) *v1alpha2.ScheduledJob {
+	if id == "" && t.IsZero() && len(metadata) == 0 {
+		return nil
+	}
+
+	job := &v1alpha2.ScheduledJob{ID: id, Time: t}
+	if c, ok := metadata[TurndownJobCadence]; ok {
+		if cadence, err := time.Parse(time.RFC3339Nano, c); err == nil {
+			ct := metav1.NewTime(cadence)
+			job.Cadence = &ct
+		}
+	}
+
+	return job
+}
+
+// Creates the v1alpha1 job metadata for the scheduled job
+func fromScheduledJob(job *v1alpha2.ScheduledJob, jobType string, repeat string) map[string]string {
+	metadata := map[string]string{
+		TurndownJobType:   jobType,
+		TurndownJobRepeat: repeat,
+	}
+	if job.Cadence != nil {
+		metadata[TurndownJobCadence] = job.Cadence.UTC().Format(time.RFC3339Nano)
+	}
+
+	return metadata
+}
+
+// Converts a v1alpha1 job type or action to the v1alpha2 action. Unknown values are kept as is.
+func toTurndownAction(s string) v1alpha2.TurndownAction {
+	switch strings.ToLower(s) {
+	case TurndownJobTypeScaleDown:
+		return v1alpha2.ActionScaleDown
+	case TurndownJobTypeScaleUp:
+		return v1alpha2.ActionScaleUp
+	}
+
+	return v1alpha2.TurndownAction(s)
+}
+
+// Converts a v1alpha2 action to the v1alpha1 job type or action. Unknown values are kept as is.
+func fromTurndownAction(a v1alpha2.TurndownAction) string {
+	switch a {
+	case v1alpha2.ActionScaleDown:
+		return TurndownJobTypeScaleDown
+	case v1alpha2.ActionScaleUp:
+		return TurndownJobTypeScaleUp
+	}
+
+	return string(a)
+}
+
+// Converts a v1alpha1 repeat type to the v1alpha2 repeat frequency. An empty repeat type does not repeat,
+// and unknown values are kept as is.
+func toRepeatFrequency(s string) v1alpha2.RepeatFrequency {
+	switch strings.ToLower(s) {
+	case "", TurndownJobRepeatNone:
+		return v1alpha2.RepeatNone
+	case TurndownJobRepeatDaily:
+		return v1alpha2.RepeatDaily
+	case TurndownJobRepeatWeekly:
+		return v1alpha2.RepeatWeekly
+	}
+
+	return v1alpha2.RepeatFrequency(s)
+}
+
+// Converts a v1alpha2 repeat frequency to the v1alpha1 repeat type. An empty frequency does not repeat,
+// and unknown values are kept as is.
+func fromRepeatFrequency(f v1alpha2.RepeatFrequency) string {
+	switch f {
+	case "", v1alpha2.RepeatNone:
+		return TurndownJobRepeatNone
+	case v1alpha2.RepeatDaily:
+		return TurndownJobRepeatDaily
+	case v1alpha2.RepeatWeekly:
+		return TurndownJobRepeatWeekly
+	}
+
+	return string(f)
+}
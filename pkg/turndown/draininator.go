@@ -19,6 +19,12 @@ import (
 	"k8s.io/apimachinery/pkg/fields"
 	"k8s.io/apimachinery/pkg/util/wait"
 	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/record"
+)
+
+const (
+	// Annotation set on nodes cordoned by turndown
+	KubecostTurnDownCordoned = "kubecost.kubernetes.io/turn-down-cordoned"
 )
 
 // Draininator is the type used to drain a specific kubernetes node. Much like
@@ -33,14 +39,17 @@ type Draininator struct {
 	force              bool
 	ignoreDaemonSets   bool
 	deleteLocalData    bool
+	stopCh             <-chan struct{}
+	recorder           record.EventRecorder
 	log                logging.NamedLogger
 }
 
 // PodFilter definition which is used to determine which pods to evict from a node.
 type PodFilter func(v1.Pod) (bool, error)
 
-// Creates a new Draininator instance for a specific node.
-func NewDraininator(client kubernetes.Interface, node string) *Draininator {
+// Creates a new Draininator instance for a specific node. Once stopCh is closed, the drain stops
+// waiting on pod deletions and returns InterruptedErr. Drain results are recorded as events on the node.
+func NewDraininator(client kubernetes.Interface, node string, stopCh <-chan struct{}, recorder record.EventRecorder) *Draininator {
 	return &Draininator{
 		client: client,
 		node:   node,
@@ -50,6 +59,8 @@ func NewDraininator(client kubernetes.Interface, node string) *Draininator {
 		force:              true,
 		deleteLocalData:    true,
 		ignoreDaemonSets:   true,
+		stopCh:             stopCh,
+		recorder:           recorder,
 		log:                logging.NamedLogger("Draininator"),
 	}
 }
@@ -59,15 +70,21 @@ func (d *Draininator) Drain() error {
 	d.log.Log("Draining Node: %s", d.node)
 	err := d.CordonNode()
 	if err != nil {
+		d.recorder.Eventf(nodeReference(d.node), v1.EventTypeWarning, NodeDrainFailed, "Failed to cordon node for turndown: %s", err.Error())
 		return err
 	}
 
 	err = d.DeletePodsOnNode()
+	if err == InterruptedErr {
+		return err
+	}
 	if err != nil {
+		d.recorder.Eventf(nodeReference(d.node), v1.EventTypeWarning, NodeDrainFailed, "Failed to drain node for turndown: %s", err.Error())
 		return err
 	}
 
 	d.log.Log("Node: %s was Drained Successfully", d.node)
+	d.recorder.Event(nodeReference(d.node), v1.EventTypeNormal, NodeDrained, "Drained node for turndown")
 	return nil
 }
 
@@ -84,12 +101,41 @@ func (d *Draininator) CordonNode() error {
 
 	_, err = patcher.PatchNode(d.client, *node, func(n *v1.Node) error {
 		n.Spec.Unschedulable = true
+		if n.Annotations == nil {
+			n.Annotations = make(map[string]string)
+		}
+		n.Annotations[KubecostTurnDownCordoned] = "true"
 		return nil
 	})
 
 	return err
 }
 
+// Uncordons the node if it was cordoned by turndown. Nodes cordoned by other means are left as is.
+func (d *Draininator) UncordonNode() error {
+	node, err := d.client.CoreV1().Nodes().Get(d.node, metav1.GetOptions{})
+	if err != nil {
+		return err
+	}
+	if _, ok := node.Annotations[KubecostTurnDownCordoned]; !ok {
+		return nil
+	}
+
+	d.log.SLog("Uncordoning Node: %s", d.node)
+
+	_, err = patcher.PatchNode(d.client, *node, func(n *v1.Node) error {
+		n.Spec.Unschedulable = false
+		delete(n.Annotations, KubecostTurnDownCordoned)
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	d.recorder.Event(nodeReference(d.node), v1.EventTypeNormal, NodeUncordoned, "Uncordoned node after turndown")
+	return nil
+}
+
 // Deletes or evicts the pods on the node that qualify for eviction
 func (d *Draininator) DeletePodsOnNode() error {
 	pods, err := d.podsToDelete()
@@ -319,6 +365,8 @@ func (d *Draininator) deletePods(pods []v1.Pod) error {
 		return nil
 	case <-time.After(globalTimeout):
 		return fmt.Errorf("Timed out while attempting to delete pods.")
+	case <-d.stopCh:
+		return InterruptedErr
 	}
 }
 
@@ -385,6 +433,8 @@ func (d *Draininator) evictPods(pods []v1.Pod, policyGroupVersion string) error
 		return nil
 	case <-time.After(globalTimeout):
 		return fmt.Errorf("Timed out while attempting to delete pods.")
+	case <-d.stopCh:
+		return InterruptedErr
 	}
 }
 
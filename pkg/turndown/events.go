@@ -0,0 +1,70 @@
+package turndown
+
+import (
+	schedulescheme "github.com/kubecost/cluster-turndown/pkg/generated/clientset/versioned/scheme"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/kubernetes/scheme"
+	typedcorev1 "k8s.io/client-go/kubernetes/typed/core/v1"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/klog"
+)
+
+// Event reasons recorded for each turndown step, on the TurndownSchedule as well as the nodes and
+// workloads affected by the step
+const (
+	EnvironmentPrepared      = "EnvironmentPrepared"
+	EnvironmentPrepareFailed = "EnvironmentPrepareFailed"
+
+	ScaleDownComplete    = "ScaleDownComplete"
+	ScaleDownFailed      = "ScaleDownFailed"
+	ScaleDownInterrupted = "ScaleDownInterrupted"
+	ScaleUpComplete      = "ScaleUpComplete"
+	ScaleUpFailed        = "ScaleUpFailed"
+	ScaleUpInterrupted   = "ScaleUpInterrupted"
+
+	WorkloadsFlattened = "WorkloadsFlattened"
+	WorkloadsExpanded  = "WorkloadsExpanded"
+	JobsSuspended      = "JobsSuspended"
+	JobsResumed        = "JobsResumed"
+	FlattenFailed      = "FlattenFailed"
+	ExpandFailed       = "ExpandFailed"
+
+	NodeDrained     = "NodeDrained"
+	NodeDrainFailed = "NodeDrainFailed"
+	NodeUncordoned  = "NodeUncordoned"
+
+	NodePoolsResized     = "NodePoolsResized"
+	NodePoolResizeFailed = "NodePoolResizeFailed"
+	NodePoolsSkipped     = "NodePoolsSkipped"
+
+	AutoscalerPaused       = "AutoscalerPaused"
+	AutoscalerResumed      = "AutoscalerResumed"
+	AutoscalerPauseFailed  = "AutoscalerPauseFailed"
+	AutoscalerResumeFailed = "AutoscalerResumeFailed"
+)
+
+// NewEventRecorder creates an EventRecorder which records events for TurndownSchedule resources as well as
+// core kubernetes resources.
+func NewEventRecorder(kubeclientset kubernetes.Interface) record.EventRecorder {
+	utilruntime.Must(schedulescheme.AddToScheme(scheme.Scheme))
+
+	eventBroadcaster := record.NewBroadcaster()
+	eventBroadcaster.StartLogging(klog.Infof)
+	eventBroadcaster.StartRecordingToSink(&typedcorev1.EventSinkImpl{Interface: kubeclientset.CoreV1().Events("")})
+
+	return eventBroadcaster.NewRecorder(scheme.Scheme, corev1.EventSource{Component: controllerAgentName})
+}
+
+// Creates a reference to a node for recording events. Like the kubelet, the node name is used as the
+// uid so the events are listed by kubectl describe.
+func nodeReference(name string) *corev1.ObjectReference {
+	return &corev1.ObjectReference{
+		Kind: "Node",
+		Name: name,
+		UID:  types.UID(name),
+	}
+}
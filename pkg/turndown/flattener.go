@@ -9,10 +9,13 @@ import (
 
 	appsv1 "k8s.io/api/apps/v1"
 	v1b1 "k8s.io/api/batch/v1beta1"
+	v1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
 
 	"k8s.io/apimachinery/pkg/util/intstr"
 	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/record"
 
 	"k8s.io/klog"
 )
@@ -30,14 +33,18 @@ const (
 type Flattener struct {
 	client          kubernetes.Interface
 	omitDeployments []string
+	recorder        record.EventRecorder
+	report          *RunReport
 	log             logging.NamedLogger
 }
 
-// Creates a new Draininator instance for a specific node.
-func NewFlattener(client kubernetes.Interface, omitDeployments []string) *Flattener {
+// Creates a new Flattener instance. Changes to deployments and daemonsets are recorded as events on
+// the workload.
+func NewFlattener(client kubernetes.Interface, omitDeployments []string, recorder record.EventRecorder) *Flattener {
 	return &Flattener{
 		client:          client,
 		omitDeployments: omitDeployments,
+		recorder:        recorder,
 		log:             logging.NamedLogger("Flattener"),
 	}
 }
@@ -214,6 +221,7 @@ func (d *Flattener) SuspendJobs() error {
 
 // Flatten
 func (d *Flattener) FlattenDeployment(dep appsv1.Deployment) error {
+	updated := false
 	_, err := patcher.PatchDeployment(d.client, dep, func(deployment *appsv1.Deployment) error {
 		updateEvictFlag := false
 		updateReplicas := false
@@ -231,13 +239,16 @@ func (d *Flattener) FlattenDeployment(dep appsv1.Deployment) error {
 			return patcher.NoUpdates
 		}
 
+		updated = true
 		return nil
 	})
 
+	d.recordResult(&dep, updated, err, FlattenFailed, WorkloadsFlattened, "Flattened for turndown")
 	return err
 }
 
 func (d *Flattener) ExpandDeployment(dep appsv1.Deployment) error {
+	updated := false
 	_, err := patcher.PatchDeployment(d.client, dep, func(deployment *appsv1.Deployment) error {
 		updateEvictFlag := false
 		updateReplicas := false
@@ -255,13 +266,16 @@ func (d *Flattener) ExpandDeployment(dep appsv1.Deployment) error {
 			return patcher.NoUpdates
 		}
 
+		updated = true
 		return nil
 	})
 
+	d.recordResult(&dep, updated, err, ExpandFailed, WorkloadsExpanded, "Expanded after turndown")
 	return err
 }
 
 func (d *Flattener) FlattenDaemonSet(ds appsv1.DaemonSet) error {
+	updated := false
 	_, err := patcher.PatchDaemonSet(d.client, ds, func(daemonset *appsv1.DaemonSet) error {
 		updateEvictFlag := d.setSafeEvictDaemonSet(daemonset)
 
@@ -269,13 +283,16 @@ func (d *Flattener) FlattenDaemonSet(ds appsv1.DaemonSet) error {
 			return patcher.NoUpdates
 		}
 
+		updated = true
 		return nil
 	})
 
+	d.recordResult(&ds, updated, err, FlattenFailed, WorkloadsFlattened, "Flattened for turndown")
 	return err
 }
 
 func (d *Flattener) ExpandDaemonSet(ds appsv1.DaemonSet) error {
+	updated := false
 	_, err := patcher.PatchDaemonSet(d.client, ds, func(daemonset *appsv1.DaemonSet) error {
 		updateEvictFlag := d.resetSafeEvictDaemonSet(daemonset)
 
@@ -283,12 +300,40 @@ func (d *Flattener) ExpandDaemonSet(ds appsv1.DaemonSet) error {
 			return patcher.NoUpdates
 		}
 
+		updated = true
 		return nil
 	})
 
+	d.recordResult(&ds, updated, err, ExpandFailed, WorkloadsExpanded, "Expanded after turndown")
 	return err
 }
 
+// Records an event on the workload if the patch failed, or if the workload was updated. Updated
+// workloads are also recorded on the run report, if set.
+func (d *Flattener) recordResult(obj runtime.Object, updated bool, err error, failedReason string, reason string, message string) {
+	if err != nil {
+		d.recorder.Eventf(obj, v1.EventTypeWarning, failedReason, "%s failed: %s", message, err.Error())
+		return
+	}
+
+	if updated {
+		d.recorder.Event(obj, v1.EventTypeNormal, reason, message)
+		d.report.AddWorkload(workloadName(obj))
+	}
+}
+
+// The kind, namespace and name of a deployment or daemonset, ie: deployment/default/api
+func workloadName(obj runtime.Object) string {
+	switch w := obj.(type) {
+	case *appsv1.Deployment:
+		return fmt.Sprintf("deployment/%s/%s", w.Namespace, w.Name)
+	case *appsv1.DaemonSet:
+		return fmt.Sprintf("daemonset/%s/%s", w.Namespace, w.Name)
+	default:
+		return obj.GetObjectKind().GroupVersionKind().Kind
+	}
+}
+
 func (d *Flattener) SuspendJob(cronJob v1b1.CronJob) error {
 	_, err := patcher.PatchCronJob(d.client, cronJob, func(job *v1b1.CronJob) error {
 		var previousValue *bool
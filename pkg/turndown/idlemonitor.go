@@ -0,0 +1,246 @@
+package turndown
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/kubecost/cluster-turndown/pkg/apis/turndownschedule/v1alpha1"
+	clientset "github.com/kubecost/cluster-turndown/pkg/generated/clientset/versioned"
+	"github.com/kubecost/cluster-turndown/pkg/logging"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/kubernetes"
+)
+
+const (
+	IdleMonitorInterval = 1 * time.Minute
+)
+
+var (
+	// Namespaces which are never counted towards cluster utilization
+	IdleSystemNamespaces = []string{"kube-system", "kube-public", "kube-node-lease"}
+
+	podMetricsResource = schema.GroupVersionResource{
+		Group:    "metrics.k8s.io",
+		Version:  "v1beta1",
+		Resource: "pods",
+	}
+)
+
+// Utilization is the CPU and memory used by workloads as a percentage of node allocatable
+type Utilization struct {
+	CPU    float64
+	Memory float64
+}
+
+// IdleMonitor samples the cluster utilization and triggers a scale down on the current schedule once
+// the utilization has stayed below the idle policy thresholds for the entire window.
+type IdleMonitor struct {
+	client    kubernetes.Interface
+	tdClient  clientset.Interface
+	dynamic   dynamic.Interface
+	scheduler *TurndownScheduler
+	idleSince time.Time
+	log       logging.NamedLogger
+}
+
+// Creates a new IdleMonitor instance
+func NewIdleMonitor(client kubernetes.Interface, tdClient clientset.Interface, dynamic dynamic.Interface, scheduler *TurndownScheduler) *IdleMonitor {
+	return &IdleMonitor{
+		client:    client,
+		tdClient:  tdClient,
+		dynamic:   dynamic,
+		scheduler: scheduler,
+		log:       logging.NamedLogger("IdleMonitor"),
+	}
+}
+
+// Run samples the cluster utilization on an interval until the stop channel is closed.
+func (im *IdleMonitor) Run(stopCh <-chan struct{}) {
+	go wait.Until(im.check, IdleMonitorInterval, stopCh)
+}
+
+func (im *IdleMonitor) check() {
+	policy := im.idlePolicy()
+	schedule := im.scheduler.GetSchedule()
+
+	// Only sample while there is a policy and the cluster is scaled up
+	if policy == nil || schedule == nil || schedule.Current != TurndownJobTypeScaleDown {
+		im.idleSince = time.Time{}
+		return
+	}
+
+	utilization, err := im.utilization(policy)
+	if err != nil {
+		im.log.Warn("Failed to determine cluster utilization: %s", err.Error())
+		return
+	}
+
+	if !isIdle(policy, utilization) {
+		im.idleSince = time.Time{}
+		return
+	}
+
+	now := time.Now()
+	if im.idleSince.IsZero() {
+		im.log.Log("Cluster is idle. CPU: %.1f%%, Memory: %.1f%%", utilization.CPU, utilization.Memory)
+		im.idleSince = now
+		return
+	}
+
+	if now.Sub(im.idleSince) < policy.Window.Duration {
+		return
+	}
+
+	reason := fmt.Sprintf("%sCPU %.1f%%, Memory %.1f%% below thresholds for %s", ScaleDownReasonIdlePrefix,
+		utilization.CPU, utilization.Memory, policy.Window.Duration)
+
+	err = im.scheduler.TriggerScaleDown(reason)
+	if err != nil {
+		im.log.Err("Failed to trigger scale down: %s", err.Error())
+	}
+
+	im.idleSince = time.Time{}
+}
+
+// Locates the idle policy on the active TurndownSchedule resource
+func (im *IdleMonitor) idlePolicy() *v1alpha1.IdlePolicy {
+	tds, err := im.tdClient.KubecostV1alpha1().TurndownSchedules().List(metav1.ListOptions{})
+	if err != nil {
+		im.log.Warn("Failed to list TurndownSchedules: %s", err.Error())
+		return nil
+	}
+
+	for _, td := range tds.Items {
+		if td.Status.State == ScheduleStateSuccess {
+			return td.Spec.IdlePolicy
+		}
+	}
+
+	return nil
+}
+
+// Determines the utilization of the cluster, excluding system namespaces, using pod requests or metrics
+// server usage.
+func (im *IdleMonitor) utilization(policy *v1alpha1.IdlePolicy) (*Utilization, error) {
+	nodes, err := im.client.CoreV1().Nodes().List(metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	var allocatableCPU, allocatableMemory int64
+	for _, n := range nodes.Items {
+		allocatableCPU += n.Status.Allocatable.Cpu().MilliValue()
+		allocatableMemory += n.Status.Allocatable.Memory().Value()
+	}
+
+	if allocatableCPU == 0 || allocatableMemory == 0 {
+		return nil, fmt.Errorf("Failed to determine node allocatable resources")
+	}
+
+	excluded := make(map[string]bool)
+	for _, ns := range IdleSystemNamespaces {
+		excluded[ns] = true
+	}
+	excluded[turndownNamespace()] = true
+	for _, ns := range policy.ExcludedNamespaces {
+		excluded[ns] = true
+	}
+
+	var cpu, memory int64
+	if policy.UseMetrics {
+		cpu, memory, err = im.metricsUsage(excluded)
+	} else {
+		cpu, memory, err = im.requests(excluded)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &Utilization{
+		CPU:    100.0 * float64(cpu) / float64(allocatableCPU),
+		Memory: 100.0 * float64(memory) / float64(allocatableMemory),
+	}, nil
+}
+
+// Sums the CPU (millicores) and memory (bytes) requests of the running pods
+func (im *IdleMonitor) requests(excluded map[string]bool) (int64, int64, error) {
+	pods, err := im.client.CoreV1().Pods(metav1.NamespaceAll).List(metav1.ListOptions{})
+	if err != nil {
+		return 0, 0, err
+	}
+
+	var cpu, memory int64
+	for _, pod := range pods.Items {
+		if excluded[pod.Namespace] || pod.Status.Phase == v1.PodSucceeded || pod.Status.Phase == v1.PodFailed {
+			continue
+		}
+
+		for _, container := range pod.Spec.Containers {
+			cpu += container.Resources.Requests.Cpu().MilliValue()
+			memory += container.Resources.Requests.Memory().Value()
+		}
+	}
+
+	return cpu, memory, nil
+}
+
+// Sums the CPU (millicores) and memory (bytes) usage of the pods reported by metrics-server
+func (im *IdleMonitor) metricsUsage(excluded map[string]bool) (int64, int64, error) {
+	list, err := im.dynamic.Resource(podMetricsResource).Namespace(metav1.NamespaceAll).List(metav1.ListOptions{})
+	if err != nil {
+		return 0, 0, err
+	}
+
+	var cpu, memory int64
+	for _, item := range list.Items {
+		if excluded[item.GetNamespace()] {
+			continue
+		}
+
+		containers, _, err := unstructured.NestedSlice(item.Object, "containers")
+		if err != nil {
+			continue
+		}
+
+		for _, c := range containers {
+			container, ok := c.(map[string]interface{})
+			if !ok {
+				continue
+			}
+
+			usage, _, _ := unstructured.NestedStringMap(container, "usage")
+			if q, err := resource.ParseQuantity(usage["cpu"]); err == nil {
+				cpu += q.MilliValue()
+			}
+			if q, err := resource.ParseQuantity(usage["memory"]); err == nil {
+				memory += q.Value()
+			}
+		}
+	}
+
+	return cpu, memory, nil
+}
+
+// The cluster is idle when the utilization is below every non-zero threshold on the policy
+func isIdle(policy *v1alpha1.IdlePolicy, utilization *Utilization) bool {
+	if policy.CPUThreshold <= 0 && policy.MemoryThreshold <= 0 {
+		return false
+	}
+
+	if policy.CPUThreshold > 0 && utilization.CPU >= float64(policy.CPUThreshold) {
+		return false
+	}
+
+	if policy.MemoryThreshold > 0 && utilization.Memory >= float64(policy.MemoryThreshold) {
+		return false
+	}
+
+	return true
+}
@@ -0,0 +1,235 @@
+package turndown
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/kubecost/cluster-turndown/pkg/logging"
+	"github.com/kubecost/cluster-turndown/pkg/turndown/provider"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/kubernetes"
+)
+
+const (
+	KarpenterGroup               = "karpenter.sh"
+	KarpenterNodePoolResource    = "nodepools"
+	KarpenterProvisionerResource = "provisioners"
+	KarpenterNodePoolLabel       = "karpenter.sh/nodepool"
+	KarpenterProvisionerLabel    = "karpenter.sh/provisioner-name"
+
+	KubecostTurnDownLimits = "kubecost.kubernetes.io/turn-down-limits"
+)
+
+// Karpenter is the type used to stop Karpenter from provisioning nodes during turndown. Karpenter
+// launched nodes are not part of any node pool the provider can resize, so provisioning is disabled
+// by setting the NodePool (or Provisioner) limits to zero, and the launched nodes are deleted.
+type Karpenter struct {
+	client   kubernetes.Interface
+	dynamic  dynamic.Interface
+	resource schema.GroupVersionResource
+	zero     map[string]interface{}
+	label    string
+	log      logging.NamedLogger
+}
+
+// Creates a new Karpenter instance if Karpenter NodePools or Provisioners are served by the cluster.
+func NewKarpenter(client kubernetes.Interface, dynamic dynamic.Interface) (*Karpenter, bool) {
+	if dynamic == nil {
+		return nil, false
+	}
+
+	k := &Karpenter{
+		client:  client,
+		dynamic: dynamic,
+		log:     logging.NamedLogger("Karpenter"),
+	}
+
+	// NodePools replaced Provisioners, and set resource limits directly on spec.limits rather
+	// than spec.limits.resources
+	if resource, ok := provider.DiscoverResource(client, KarpenterGroup, KarpenterNodePoolResource); ok {
+		k.resource = resource
+		k.zero = map[string]interface{}{"cpu": "0", "memory": "0"}
+		k.label = KarpenterNodePoolLabel
+		return k, true
+	}
+
+	if resource, ok := provider.DiscoverResource(client, KarpenterGroup, KarpenterProvisionerResource); ok {
+		k.resource = resource
+		k.zero = map[string]interface{}{
+			"resources": map[string]interface{}{"cpu": "0", "memory": "0"},
+		}
+		k.label = KarpenterProvisionerLabel
+		return k, true
+	}
+
+	return nil, false
+}
+
+// DisableProvisioning sets the limits on all Karpenter NodePools to zero, storing the original limits
+// in an annotation. Karpenter will not launch new nodes for pending pods while the limits are zero.
+// Returns an error listing any NodePools which could not be disabled. NodePools which were disabled are
+// skipped when called again.
+func (k *Karpenter) DisableProvisioning() error {
+	list, err := k.dynamic.Resource(k.resource).List(metav1.ListOptions{})
+	if err != nil {
+		return err
+	}
+
+	var errs []string
+	for _, item := range list.Items {
+		// Already disabled -- do not overwrite the original limits
+		if _, ok := item.GetAnnotations()[KubecostTurnDownLimits]; ok {
+			continue
+		}
+
+		limits, _, err := unstructured.NestedMap(item.Object, "spec", "limits")
+		if err != nil {
+			k.log.Err("Failed to read limits for: %s - %s", item.GetName(), err.Error())
+			errs = append(errs, fmt.Sprintf("%s: %s", item.GetName(), err.Error()))
+			continue
+		}
+
+		previous, err := json.Marshal(limits)
+		if err != nil {
+			return err
+		}
+
+		k.log.SLog("Setting limits for: %s to zero", item.GetName())
+		err = k.patchLimits(&item, k.zero, string(previous))
+		if err != nil {
+			k.log.Err("Failed to disable provisioning for: %s - %s", item.GetName(), err.Error())
+			errs = append(errs, fmt.Sprintf("%s: %s", item.GetName(), err.Error()))
+		}
+	}
+
+	if len(errs) > 0 {
+		return fmt.Errorf("Failed to disable provisioning for %d %s: [%s]", len(errs), k.resource.Resource, strings.Join(errs, ", "))
+	}
+
+	return nil
+}
+
+// RestoreProvisioning restores the limits on all Karpenter NodePools which were set to zero by turndown.
+// Returns an error listing any NodePools which could not be restored.
+func (k *Karpenter) RestoreProvisioning() error {
+	list, err := k.dynamic.Resource(k.resource).List(metav1.ListOptions{})
+	if err != nil {
+		return err
+	}
+
+	var errs []string
+	for _, item := range list.Items {
+		previous, ok := item.GetAnnotations()[KubecostTurnDownLimits]
+		if !ok {
+			continue
+		}
+
+		var limits map[string]interface{}
+		err := json.Unmarshal([]byte(previous), &limits)
+		if err != nil {
+			k.log.Err("Failed to parse limits annotation for: %s - %s", item.GetName(), err.Error())
+			errs = append(errs, fmt.Sprintf("%s: %s", item.GetName(), err.Error()))
+			continue
+		}
+
+		k.log.SLog("Restoring limits for: %s", item.GetName())
+		err = k.patchLimits(&item, limits, "")
+		if err != nil {
+			k.log.Err("Failed to restore provisioning for: %s - %s", item.GetName(), err.Error())
+			errs = append(errs, fmt.Sprintf("%s: %s", item.GetName(), err.Error()))
+		}
+	}
+
+	if len(errs) > 0 {
+		return fmt.Errorf("Failed to restore provisioning for %d %s: [%s]", len(errs), k.resource.Resource, strings.Join(errs, ", "))
+	}
+
+	return nil
+}
+
+// IsProvisioningDisabled returns true if any of the Karpenter NodePools have limits set by turndown.
+func (k *Karpenter) IsProvisioningDisabled() bool {
+	list, err := k.dynamic.Resource(k.resource).List(metav1.ListOptions{})
+	if err != nil {
+		k.log.Warn("Failed to fetch %s: %s", k.resource.Resource, err.Error())
+		return false
+	}
+
+	for _, item := range list.Items {
+		if _, ok := item.GetAnnotations()[KubecostTurnDownLimits]; ok {
+			return true
+		}
+	}
+
+	return false
+}
+
+// IsKarpenterNode returns true if the node was launched by Karpenter.
+func (k *Karpenter) IsKarpenterNode(labels map[string]string) bool {
+	_, ok := labels[k.label]
+	return ok
+}
+
+// DeleteNodes deletes all Karpenter launched nodes other than the excluded node. Karpenter terminates
+// the backing instances when the nodes are deleted.
+func (k *Karpenter) DeleteNodes(exclude string) error {
+	nodes, err := k.client.CoreV1().Nodes().List(metav1.ListOptions{
+		LabelSelector: k.label,
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, node := range nodes.Items {
+		if node.Name == exclude {
+			continue
+		}
+
+		k.log.SLog("Deleting Karpenter Node: %s", node.Name)
+		err := k.client.CoreV1().Nodes().Delete(node.Name, &metav1.DeleteOptions{})
+		if err != nil {
+			k.log.Err("Failed to delete node: %s - %s", node.Name, err.Error())
+		}
+	}
+
+	return nil
+}
+
+// Replaces the limits on the NodePool using a single json patch. If previous is empty, the limits annotation
+// is removed, otherwise it is set to previous.
+func (k *Karpenter) patchLimits(item *unstructured.Unstructured, limits map[string]interface{}, previous string) error {
+	annotationPath := "/metadata/annotations/" + strings.Replace(KubecostTurnDownLimits, "/", "~1", -1)
+
+	var ops []map[string]interface{}
+	if len(limits) == 0 {
+		ops = append(ops, map[string]interface{}{"op": "remove", "path": "/spec/limits"})
+	} else {
+		ops = append(ops, map[string]interface{}{"op": "add", "path": "/spec/limits", "value": limits})
+	}
+
+	if previous == "" {
+		ops = append(ops, map[string]interface{}{"op": "remove", "path": annotationPath})
+	} else if item.GetAnnotations() == nil {
+		ops = append(ops, map[string]interface{}{
+			"op":    "add",
+			"path":  "/metadata/annotations",
+			"value": map[string]string{KubecostTurnDownLimits: previous},
+		})
+	} else {
+		ops = append(ops, map[string]interface{}{"op": "add", "path": annotationPath, "value": previous})
+	}
+
+	data, err := json.Marshal(ops)
+	if err != nil {
+		return err
+	}
+
+	_, err = k.dynamic.Resource(k.resource).Patch(item.GetName(), types.JSONPatchType, data, metav1.PatchOptions{})
+	return err
+}
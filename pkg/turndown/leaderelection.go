@@ -0,0 +1,131 @@
+package turndown
+
+import (
+	"context"
+	"os"
+	"time"
+
+	"github.com/kubecost/cluster-turndown/pkg/logging"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/leaderelection"
+	"k8s.io/client-go/tools/leaderelection/resourcelock"
+	"k8s.io/klog"
+)
+
+const (
+	TurndownLeaseName = "cluster-turndown"
+
+	LeaseDuration = 15 * time.Second
+	RenewDeadline = 10 * time.Second
+	RetryPeriod   = 2 * time.Second
+
+	// Interval used to check whether a replica has been moved to the turndown node before campaigning
+	moveCheckInterval = 15 * time.Second
+)
+
+// RunLeaderElection campaigns for the turndown lease, and calls run with stopCh once this replica is the
+// leader. The lease is released once run returns, so a new leader does not start while the previous one
+// is still shutting down. Replicas left behind when the deployment moves to the turndown node do not
+// campaign, so leadership follows the host node migration. Losing the lease exits the process so the
+// replica restarts as a standby. Blocks until stopCh is closed.
+func RunLeaderElection(client kubernetes.Interface, manager TurndownManager, run func(stopCh <-chan struct{}), stopCh <-chan struct{}) {
+	log := logging.NamedLogger("LeaderElection")
+
+	identity := os.Getenv("POD_NAME")
+	if identity == "" {
+		identity, _ = os.Hostname()
+	}
+
+	// Wait for this replica to be replaced rather than taking over from outside of the turndown node
+	wait.PollImmediateUntil(moveCheckInterval, func() (bool, error) {
+		return !isMovingToTurndownNode(manager, log), nil
+	}, stopCh)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	elector, err := leaderelection.NewLeaderElector(leaderelection.LeaderElectionConfig{
+		Lock: &resourcelock.LeaseLock{
+			LeaseMeta: metav1.ObjectMeta{
+				Name:      TurndownLeaseName,
+				Namespace: turndownNamespace(),
+			},
+			Client: client.CoordinationV1(),
+			LockConfig: resourcelock.ResourceLockConfig{
+				Identity: identity,
+			},
+		},
+		LeaseDuration:   LeaseDuration,
+		RenewDeadline:   RenewDeadline,
+		RetryPeriod:     RetryPeriod,
+		ReleaseOnCancel: true,
+		Name:            TurndownLeaseName,
+		Callbacks: leaderelection.LeaderCallbacks{
+			OnStartedLeading: func(ctx context.Context) {
+				// The deployment may have moved while this replica was campaigning
+				if isMovingToTurndownNode(manager, log) {
+					log.Log("Releasing leadership to a replica on the turndown node")
+					cancel()
+					return
+				}
+
+				// Shutting down before leadership was acquired
+				select {
+				case <-stopCh:
+					cancel()
+					return
+				default:
+				}
+
+				log.Log("Started leading as %s", identity)
+				run(stopCh)
+				cancel()
+			},
+			OnStoppedLeading: func() {
+				select {
+				case <-stopCh:
+					log.Log("Stopped leading as %s", identity)
+				default:
+					klog.Fatalf("Lost leadership as %s", identity)
+				}
+			},
+			OnNewLeader: func(leader string) {
+				if leader != identity {
+					log.Log("Current leader is %s", leader)
+				}
+			},
+		},
+	})
+	if err != nil {
+		klog.Fatalf("Failed to create leader elector: %s", err.Error())
+	}
+
+	// The leader cancels once run returns
+	go func() {
+		<-stopCh
+		if !elector.IsLeader() {
+			cancel()
+		}
+	}()
+
+	elector.Run(ctx)
+}
+
+// Checks whether the replica is waiting to be replaced on the turndown node. Errors are treated as not
+// moving, so a failing check never prevents a leader from being elected.
+func isMovingToTurndownNode(manager TurndownManager, log logging.NamedLogger) bool {
+	moving, err := manager.IsMovingToTurndownNode()
+	if err != nil {
+		log.Warn("Failed to check for turndown node migration: %s", err.Error())
+		return false
+	}
+
+	if moving {
+		log.Log("Deployment is moving to the turndown node. Waiting to be replaced.")
+	}
+
+	return moving
+}
@@ -0,0 +1,388 @@
+package turndown
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/kubecost/cluster-turndown/pkg/turndown/provider"
+
+	v1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/kubernetes"
+)
+
+const (
+	// ConfigMap in the turndown namespace containing the night pool templates for the cluster
+	NightSwapConfigMapName = "cluster-turndown-night-swap"
+	NightSwapConfigKey     = "night-swap.json"
+
+	NightPoolReadyInterval = 15 * time.Second
+	NightPoolReadyTimeout  = 15 * time.Minute
+)
+
+// NightPoolTemplate is a node pool which is scaled up to the provided size while the day pools are
+// turned down.
+type NightPoolTemplate struct {
+	Name string `json:"name"`
+	Size int32  `json:"size"`
+}
+
+// NightSwapConfig contains the night pool templates and the day pools to swap out. If no day pools
+// are provided, all non-autoscaling pools other than the night pools are swapped out.
+type NightSwapConfig struct {
+	NightPools []NightPoolTemplate `json:"nightPools"`
+	DayPools   []string            `json:"dayPools,omitempty"`
+}
+
+// LoadNightSwapConfig loads the night swap configuration for the cluster. If the configuration does not
+// exist, nil is returned and a full turndown should occur.
+func LoadNightSwapConfig(client kubernetes.Interface) (*NightSwapConfig, error) {
+	cm, err := client.CoreV1().ConfigMaps(turndownNamespace()).Get(NightSwapConfigMapName, metav1.GetOptions{})
+	if err != nil {
+		if k8serrors.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	data, ok := cm.Data[NightSwapConfigKey]
+	if !ok {
+		return nil, fmt.Errorf("ConfigMap: %s is missing key: %s", NightSwapConfigMapName, NightSwapConfigKey)
+	}
+
+	var config NightSwapConfig
+	err = json.Unmarshal([]byte(data), &config)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(config.NightPools) == 0 {
+		return nil, fmt.Errorf("ConfigMap: %s does not contain any night pools", NightSwapConfigMapName)
+	}
+
+	return &config, nil
+}
+
+// NightPool returns the template for the node pool if it is a night pool.
+func (nsc *NightSwapConfig) NightPool(name string) (NightPoolTemplate, bool) {
+	for _, np := range nsc.NightPools {
+		if np.Name == name {
+			return np, true
+		}
+	}
+
+	return NightPoolTemplate{}, false
+}
+
+// IsDayPool returns true if the node pool should be swapped out at night.
+func (nsc *NightSwapConfig) IsDayPool(np provider.NodePool) bool {
+	if _, ok := nsc.NightPool(np.Name()); ok {
+		return false
+	}
+
+	if len(nsc.DayPools) == 0 {
+		return !np.AutoScaling()
+	}
+
+	for _, name := range nsc.DayPools {
+		if name == np.Name() {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Scales up the night pools, then cordons, drains and turns down the day pools. Workloads keep running
+// on the night pools, so nothing is flattened or suspended. Like a regular scale down, each step is
+// checkpointed and recorded, so an interrupted swap resumes where it left off.
+func (ktdm *KubernetesTurndownManager) swapToNightPools(config *NightSwapConfig, nodes []v1.Node, nodePools []provider.NodePool) error {
+	ktdm.log.Log("Swapping to Night Pools...")
+
+	var currentNodePoolID string
+	for _, n := range nodes {
+		if n.Name == ktdm.currentNode {
+			currentNodePoolID = ktdm.provider.GetPoolID(&n)
+			break
+		}
+	}
+
+	nightPools := []provider.NodePool{}
+	dayPools := []provider.NodePool{}
+	sizes := make(map[string]int32)
+	for _, np := range nodePools {
+		if template, ok := config.NightPool(np.Name()); ok {
+			nightPools = append(nightPools, np)
+			sizes[np.Name()] = template.Size
+		} else if np.Name() != currentNodePoolID && config.IsDayPool(np) {
+			dayPools = append(dayPools, np)
+		}
+	}
+
+	if len(nightPools) != len(config.NightPools) {
+		return fmt.Errorf("Failed to locate all night pools. Found %d of %d.", len(nightPools), len(config.NightPools))
+	}
+
+	notAutoScaling := false
+	ktdm.autoScaling = &notAutoScaling
+
+	// 1. Scale up the night pools to their template sizes
+	if ktdm.hasCompleted(TurndownStepNightPools) {
+		ktdm.log.Log("Night pools were scaled up before interruption. Skipping.")
+		ktdm.nodePools = nightPools
+	} else {
+		start := time.Now()
+		nightResized, _, _, err := ktdm.resizeNodePools(nightPools, func(pools []provider.NodePool) provider.NodePoolResults {
+			results := provider.NodePoolResults{}
+			for _, np := range pools {
+				results = append(results, ktdm.provider.SetNodePoolSizes([]provider.NodePool{np}, sizes[np.Name()])...)
+			}
+			return results
+		})
+		ktdm.report.AddStep(TurndownStepNightPools, start, err)
+		ktdm.nodePools = nightResized
+		if len(nightResized) > 0 {
+			ktdm.recordEvent(v1.EventTypeNormal, NodePoolsResized, "Scaled up night pools: %s", strings.Join(nodePoolNames(nightResized), ", "))
+		}
+		if err != nil {
+			ktdm.recordEvent(v1.EventTypeWarning, NodePoolResizeFailed, "Failed to scale up night pools: %s", err.Error())
+			return err
+		}
+		ktdm.completeStep(TurndownStepNightPools)
+	}
+
+	// 2. Wait until the night pools can accept the workloads from the day pools
+	ktdm.log.Log("Waiting for Night Pool Nodes to become Ready...")
+	err := ktdm.waitForReadyNodes(sizes)
+	if err != nil {
+		return err
+	}
+
+	// 3. Cordon and drain the day pool nodes. The day pools are only turned down once all of their
+	// workloads are moved to the night pools.
+	isDayPool := make(map[string]bool)
+	for _, np := range dayPools {
+		isDayPool[np.Name()] = true
+	}
+
+	var errs []string
+	for _, n := range nodes {
+		if n.Name == ktdm.currentNode || !isDayPool[ktdm.provider.GetPoolID(&n)] {
+			continue
+		}
+
+		err := ktdm.drainNode(n.Name)
+		if err == InterruptedErr {
+			return err
+		}
+		if err != nil {
+			errs = append(errs, fmt.Sprintf("%s: %s", n.Name, err.Error()))
+		}
+	}
+
+	if len(errs) > 0 {
+		return fmt.Errorf("Failed to drain %d day pool node(s): [%s]", len(errs), strings.Join(errs, ", "))
+	}
+
+	if ktdm.isStopping() {
+		return InterruptedErr
+	}
+
+	// 4. Turn down the day pools, pausing the cluster-autoscaler so it does not scale them back up
+	if len(dayPools) > 0 {
+		err := ktdm.pauseClusterAutoscaler()
+		if err != nil {
+			return err
+		}
+	}
+
+	ktdm.log.Log("Resizing day node groups to 0...")
+	start := time.Now()
+	dayResized, _, _, err := ktdm.resizeNodePools(dayPools, func(pools []provider.NodePool) provider.NodePoolResults {
+		return ktdm.provider.SetNodePoolSizes(pools, 0)
+	})
+	ktdm.report.AddStep(TurndownStepResize, start, err)
+	ktdm.nodePools = append(ktdm.nodePools, dayResized...)
+	if len(dayResized) > 0 {
+		ktdm.recordEvent(v1.EventTypeNormal, NodePoolsResized, "Scaled down day pools: %s", strings.Join(nodePoolNames(dayResized), ", "))
+	}
+	if err != nil {
+		ktdm.recordEvent(v1.EventTypeWarning, NodePoolResizeFailed, "Failed to scale down day pools: %s", err.Error())
+		return err
+	}
+	ktdm.completeStep(TurndownStepResize)
+
+	return nil
+}
+
+// Restores the day pools, then cordons, drains and resets the night pools back to their sizes prior to
+// turndown. Like a regular scale up, each step is checkpointed and recorded, so an interrupted swap
+// resumes where it left off.
+func (ktdm *KubernetesTurndownManager) swapToDayPools(config *NightSwapConfig) error {
+	ktdm.log.Log("Swapping to Day Pools...")
+
+	nightPools := []provider.NodePool{}
+	dayPools := []provider.NodePool{}
+	for _, np := range ktdm.nodePools {
+		if _, ok := config.NightPool(np.Name()); ok {
+			nightPools = append(nightPools, np)
+		} else {
+			dayPools = append(dayPools, np)
+		}
+	}
+
+	// 1. Restore the day pools, recording the restored sizes to wait on
+	sizes := make(map[string]int32)
+	if ktdm.hasCompleted(TurndownStepResize) {
+		ktdm.log.Log("Day pools were reset before interruption. Skipping.")
+		for _, np := range dayPools {
+			sizes[np.Name()] = np.NodeCount()
+		}
+	} else {
+		start := time.Now()
+		resized, failed, _, err := ktdm.resizeNodePools(dayPools, func(pools []provider.NodePool) provider.NodePoolResults {
+			results := ktdm.provider.ResetNodePoolSizes(pools)
+			for _, r := range results {
+				if r.Outcome == provider.NodePoolResized {
+					sizes[r.NodePool.Name()] = r.RequestedSize
+				}
+			}
+			return results
+		})
+		ktdm.report.AddStep(TurndownStepResize, start, err)
+		if len(resized) > 0 {
+			ktdm.recordEvent(v1.EventTypeNormal, NodePoolsResized, "Reset day pools to pre-turndown sizes: %s", strings.Join(nodePoolNames(resized), ", "))
+		}
+		if err != nil {
+			ktdm.recordEvent(v1.EventTypeWarning, NodePoolResizeFailed, "Failed to reset day pools: %s", err.Error())
+			ktdm.nodePools = append(failed, nightPools...)
+			return err
+		}
+		ktdm.completeStep(TurndownStepResize)
+	}
+
+	err := ktdm.resumeClusterAutoscaler()
+	if err != nil {
+		return err
+	}
+
+	if ktdm.isStopping() {
+		return InterruptedErr
+	}
+
+	// 2. Wait until the day pools can accept the workloads from the night pools
+	ktdm.log.Log("Waiting for Day Pool Nodes to become Ready...")
+	err = ktdm.waitForReadyNodes(sizes)
+	if err != nil {
+		ktdm.nodePools = nightPools
+		return err
+	}
+
+	// 3. Cordon and drain the night pool nodes. The night pools are only reset once all of their
+	// workloads are moved back to the day pools.
+	nodes, err := ktdm.client.CoreV1().Nodes().List(metav1.ListOptions{})
+	if err != nil {
+		ktdm.nodePools = nightPools
+		return err
+	}
+
+	var errs []string
+	for _, n := range nodes.Items {
+		if n.Name == ktdm.currentNode {
+			continue
+		}
+
+		if _, ok := config.NightPool(ktdm.provider.GetPoolID(&n)); !ok {
+			continue
+		}
+
+		err := ktdm.drainNode(n.Name)
+		if err == InterruptedErr {
+			ktdm.nodePools = nightPools
+			return err
+		}
+		if err != nil {
+			errs = append(errs, fmt.Sprintf("%s: %s", n.Name, err.Error()))
+		}
+	}
+
+	if len(errs) > 0 {
+		ktdm.nodePools = nightPools
+		return fmt.Errorf("Failed to drain %d night pool node(s): [%s]", len(errs), strings.Join(errs, ", "))
+	}
+
+	if ktdm.isStopping() {
+		ktdm.nodePools = nightPools
+		return InterruptedErr
+	}
+
+	// 4. Reset the night pools to their sizes prior to turndown
+	if ktdm.hasCompleted(TurndownStepNightPools) {
+		ktdm.log.Log("Night pools were reset before interruption. Skipping.")
+	} else {
+		start := time.Now()
+		resized, failed, _, err := ktdm.resizeNodePools(nightPools, ktdm.provider.ResetNodePoolSizes)
+		ktdm.report.AddStep(TurndownStepNightPools, start, err)
+		if len(resized) > 0 {
+			ktdm.recordEvent(v1.EventTypeNormal, NodePoolsResized, "Reset night pools to pre-turndown sizes: %s", strings.Join(nodePoolNames(resized), ", "))
+		}
+		if err != nil {
+			ktdm.recordEvent(v1.EventTypeWarning, NodePoolResizeFailed, "Failed to reset night pools: %s", err.Error())
+			ktdm.nodePools = failed
+			return err
+		}
+		ktdm.completeStep(TurndownStepNightPools)
+	}
+
+	// Night pool nodes which remain after the reset are still cordoned, so uncordon them
+	err = ktdm.uncordonNodes()
+	if err != nil {
+		ktdm.log.Err("Failed to uncordon nodes: %s", err.Error())
+	}
+
+	ktdm.nodePools = nil
+	ktdm.autoScaling = nil
+
+	return nil
+}
+
+// Waits until each node pool has at least the provided number of ready, schedulable nodes.
+func (ktdm *KubernetesTurndownManager) waitForReadyNodes(sizes map[string]int32) error {
+	return wait.PollImmediate(NightPoolReadyInterval, NightPoolReadyTimeout, func() (bool, error) {
+		nodes, err := ktdm.client.CoreV1().Nodes().List(metav1.ListOptions{})
+		if err != nil {
+			return false, err
+		}
+
+		ready := make(map[string]int32)
+		for _, n := range nodes.Items {
+			if n.Spec.Unschedulable || !isNodeReady(&n) {
+				continue
+			}
+
+			ready[ktdm.provider.GetPoolID(&n)]++
+		}
+
+		for pool, size := range sizes {
+			if ready[pool] < size {
+				return false, nil
+			}
+		}
+
+		return true, nil
+	})
+}
+
+func isNodeReady(node *v1.Node) bool {
+	for _, condition := range node.Status.Conditions {
+		if condition.Type == v1.NodeReady {
+			return condition.Status == v1.ConditionTrue
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,123 @@
+package turndown
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/kubecost/cluster-turndown/pkg/apis/turndownschedule/v1alpha1"
+	"github.com/kubecost/cluster-turndown/pkg/turndown/provider"
+
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+// NodePoolTargets maps node pool names to the size they should be scaled down to. Node pools without
+// a target are scaled down to 0.
+type NodePoolTargets map[string]intstr.IntOrString
+
+// NewNodePoolTargets creates NodePoolTargets from the targets on a TurndownSchedule spec.
+func NewNodePoolTargets(targets []v1alpha1.NodePoolTarget) NodePoolTargets {
+	if len(targets) == 0 {
+		return nil
+	}
+
+	npt := make(NodePoolTargets)
+	for _, t := range targets {
+		npt[t.NodePool] = t.Size
+	}
+
+	return npt
+}
+
+// ParseNodePoolTargets parses targets encoded as pool=size pairs separated by commas, where size is
+// either a node count or a percentage, ie: default-pool=1,workers=25%
+func ParseNodePoolTargets(s string) (NodePoolTargets, error) {
+	if s == "" {
+		return nil, nil
+	}
+
+	npt := make(NodePoolTargets)
+	for _, pair := range strings.Split(s, ",") {
+		kv := strings.SplitN(pair, "=", 2)
+		if len(kv) != 2 || kv[0] == "" || kv[1] == "" {
+			return nil, &RequestError{fmt.Errorf("Invalid node pool target: %s", pair)}
+		}
+
+		npt[kv[0]] = intstr.Parse(kv[1])
+	}
+
+	return npt, npt.Validate()
+}
+
+// Validate returns a RequestError if any of the targets are negative or a percentage over 100%.
+func (npt NodePoolTargets) Validate() error {
+	for pool, size := range npt {
+		if size.Type == intstr.String && !strings.HasSuffix(size.StrVal, "%") {
+			return &RequestError{fmt.Errorf("Target for node pool: %s must be a node count or percentage, found: %s", pool, size.StrVal)}
+		}
+
+		value, err := intstr.GetValueFromIntOrPercent(&size, 100, false)
+		if err != nil {
+			return &RequestError{fmt.Errorf("Target for node pool: %s is invalid: %s", pool, err.Error())}
+		}
+
+		if value < 0 {
+			return &RequestError{fmt.Errorf("Target for node pool: %s must not be negative", pool)}
+		}
+
+		if size.Type == intstr.String && value > 100 {
+			return &RequestError{fmt.Errorf("Target for node pool: %s must not exceed 100%%", pool)}
+		}
+	}
+
+	return nil
+}
+
+// SizeFor returns the size the node pool should be scaled down to. Percentages are rounded up, so a
+// non-zero percentage keeps at least one node. The size never exceeds the current node count.
+func (npt NodePoolTargets) SizeFor(np provider.NodePool) int32 {
+	size, ok := npt[np.Name()]
+	if !ok {
+		return 0
+	}
+
+	count := int(np.NodeCount())
+	value, err := intstr.GetValueFromIntOrPercent(&size, count, true)
+	if err != nil || value < 0 {
+		return 0
+	}
+
+	if value > count {
+		return int32(count)
+	}
+
+	return int32(value)
+}
+
+// Targets returns the targets in the format of a TurndownSchedule spec, sorted by node pool.
+func (npt NodePoolTargets) Targets() []v1alpha1.NodePoolTarget {
+	if len(npt) == 0 {
+		return nil
+	}
+
+	targets := []v1alpha1.NodePoolTarget{}
+	for pool, size := range npt {
+		targets = append(targets, v1alpha1.NodePoolTarget{NodePool: pool, Size: size})
+	}
+
+	sort.Slice(targets, func(i, j int) bool {
+		return targets[i].NodePool < targets[j].NodePool
+	})
+	return targets
+}
+
+// String encodes the targets in the format accepted by ParseNodePoolTargets.
+func (npt NodePoolTargets) String() string {
+	pairs := []string{}
+	for pool, size := range npt {
+		pairs = append(pairs, fmt.Sprintf("%s=%s", pool, size.String()))
+	}
+
+	sort.Strings(pairs)
+	return strings.Join(pairs, ",")
+}
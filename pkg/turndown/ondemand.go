@@ -0,0 +1,17 @@
+package turndown
+
+import (
+	"github.com/kubecost/cluster-turndown/pkg/apis/turndownschedule/v1alpha1"
+)
+
+const (
+	// Annotation on a TurndownSchedule requesting an immediate scaledown or scaleup. The annotation is
+	// removed once the action starts.
+	KubecostTurnDownAction = "kubecost.kubernetes.io/turndown-action"
+)
+
+// ScaleDownRequest is the POST encoding used to scale down immediately. The node pool targets only apply
+// if there is no existing schedule.
+type ScaleDownRequest struct {
+	NodePoolTargets []v1alpha1.NodePoolTarget `json:"nodePoolTargets,omitempty"`
+}
@@ -0,0 +1,266 @@
+package turndown
+
+import (
+	"encoding/json"
+	"net/http"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+const (
+	OpenAPIPath = APIV1Prefix + "/openapi.json"
+)
+
+var (
+	timeType        = reflect.TypeOf(time.Time{})
+	metaTimeType    = reflect.TypeOf(metav1.Time{})
+	durationType    = reflect.TypeOf(metav1.Duration{})
+	intOrStringType = reflect.TypeOf(intstr.IntOrString{})
+	objectMetaType  = reflect.TypeOf(metav1.ObjectMeta{})
+)
+
+// OpenAPIHandler serves an OpenAPI document generated from the routes
+func OpenAPIHandler(routes []APIRoute) http.HandlerFunc {
+	document, err := json.MarshalIndent(OpenAPIDocument(routes), "", "  ")
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			w.Header().Set("Allow", http.MethodGet)
+			writeV1Error(w, &methodNotAllowedError{r.Method})
+			return
+		}
+
+		if err != nil {
+			writeV1Error(w, err)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.Write(document)
+	}
+}
+
+// OpenAPIDocument generates an OpenAPI 3 document for the routes. Schemas are derived from the request
+// and response types of each operation.
+func OpenAPIDocument(routes []APIRoute) map[string]interface{} {
+	g := &schemaGenerator{schemas: make(map[string]interface{})}
+
+	paths := make(map[string]interface{})
+	for _, route := range routes {
+		item := make(map[string]interface{})
+
+		for _, op := range route.Operations {
+			operation := map[string]interface{}{
+				"summary":     op.Summary,
+				"operationId": operationID(op.Method, route.Path),
+				"responses":   g.responses(op),
+			}
+
+			if strings.Contains(route.Path, "{name}") {
+				operation["parameters"] = []interface{}{
+					map[string]interface{}{
+						"name":     "name",
+						"in":       "path",
+						"required": true,
+						"schema":   map[string]interface{}{"type": "string"},
+					},
+				}
+			}
+
+			if op.Request != nil {
+				contentType := "application/json"
+				if op.Method == http.MethodPatch {
+					contentType = "application/merge-patch+json"
+				}
+
+				operation["requestBody"] = map[string]interface{}{
+					"content": map[string]interface{}{
+						contentType: map[string]interface{}{
+							"schema": g.schemaFor(reflect.TypeOf(op.Request)),
+						},
+					},
+				}
+			}
+
+			item[strings.ToLower(op.Method)] = operation
+		}
+
+		paths[route.Path] = item
+	}
+
+	return map[string]interface{}{
+		"openapi": "3.0.0",
+		"info": map[string]interface{}{
+			"title":   "Cluster Turndown API",
+			"version": "v1",
+		},
+		"paths": paths,
+		"components": map[string]interface{}{
+			"schemas": g.schemas,
+			"securitySchemes": map[string]interface{}{
+				"bearerAuth": map[string]interface{}{
+					"type":   "http",
+					"scheme": "bearer",
+				},
+			},
+		},
+		"security": []interface{}{
+			map[string]interface{}{"bearerAuth": []string{}},
+		},
+	}
+}
+
+// Creates an operation identifier from the method and path, ie: GET /api/v1/schedules/{name} -> getSchedulesName
+func operationID(method string, path string) string {
+	id := strings.ToLower(method)
+	for _, part := range strings.Split(strings.TrimPrefix(path, APIV1Prefix), "/") {
+		part = strings.Trim(part, "{}")
+		if part == "" {
+			continue
+		}
+
+		id += strings.ToUpper(part[:1]) + part[1:]
+	}
+
+	return id
+}
+
+// Generates JSON schemas for Go types, collecting named structs as reusable component schemas
+type schemaGenerator struct {
+	schemas map[string]interface{}
+}
+
+// Describes the data envelope returned for each response code of the operation
+func (g *schemaGenerator) responses(op APIOperation) map[string]interface{} {
+	responses := make(map[string]interface{})
+
+	for _, code := range op.Codes {
+		data := map[string]interface{}{"type": "string"}
+		if code < 300 {
+			data = map[string]interface{}{}
+			if op.Response != nil {
+				data = g.schemaFor(reflect.TypeOf(op.Response))
+			}
+		}
+
+		responses[strconv.Itoa(code)] = map[string]interface{}{
+			"description": http.StatusText(code),
+			"content": map[string]interface{}{
+				"application/json": map[string]interface{}{
+					"schema": map[string]interface{}{
+						"type": "object",
+						"properties": map[string]interface{}{
+							"code":   map[string]interface{}{"type": "integer"},
+							"status": map[string]interface{}{"type": "string"},
+							"data":   data,
+						},
+					},
+				},
+			},
+		}
+	}
+
+	return responses
+}
+
+func (g *schemaGenerator) schemaFor(t reflect.Type) map[string]interface{} {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	switch t {
+	case timeType, metaTimeType:
+		return map[string]interface{}{"type": "string", "format": "date-time"}
+	case durationType:
+		return map[string]interface{}{"type": "string", "description": "Duration, ie: 1h30m"}
+	case intOrStringType:
+		return map[string]interface{}{
+			"anyOf": []interface{}{
+				map[string]interface{}{"type": "integer"},
+				map[string]interface{}{"type": "string"},
+			},
+		}
+	case objectMetaType:
+		return map[string]interface{}{"type": "object", "description": "Standard Kubernetes object metadata"}
+	}
+
+	switch t.Kind() {
+	case reflect.Bool:
+		return map[string]interface{}{"type": "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32:
+		return map[string]interface{}{"type": "integer", "format": "int32"}
+	case reflect.Int64, reflect.Uint64:
+		return map[string]interface{}{"type": "integer", "format": "int64"}
+	case reflect.Float32, reflect.Float64:
+		return map[string]interface{}{"type": "number"}
+	case reflect.String:
+		return map[string]interface{}{"type": "string"}
+	case reflect.Slice, reflect.Array:
+		return map[string]interface{}{"type": "array", "items": g.schemaFor(t.Elem())}
+	case reflect.Map:
+		return map[string]interface{}{"type": "object", "additionalProperties": g.schemaFor(t.Elem())}
+	case reflect.Struct:
+		if t.Name() == "" {
+			return g.structSchema(t)
+		}
+
+		if _, ok := g.schemas[t.Name()]; !ok {
+			// Reserve the name before generating, in case the type refers to itself
+			g.schemas[t.Name()] = map[string]interface{}{}
+			g.schemas[t.Name()] = g.structSchema(t)
+		}
+
+		return map[string]interface{}{"$ref": "#/components/schemas/" + t.Name()}
+	}
+
+	return map[string]interface{}{}
+}
+
+func (g *schemaGenerator) structSchema(t reflect.Type) map[string]interface{} {
+	properties := make(map[string]interface{})
+
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if field.PkgPath != "" {
+			continue
+		}
+
+		tag := field.Tag.Get("json")
+		name := strings.Split(tag, ",")[0]
+		if name == "-" {
+			continue
+		}
+
+		// Embedded structs without a name are inlined into the parent
+		if field.Anonymous && name == "" {
+			embedded := field.Type
+			for embedded.Kind() == reflect.Ptr {
+				embedded = embedded.Elem()
+			}
+
+			if embedded.Kind() == reflect.Struct {
+				inlined := g.structSchema(embedded)
+				for k, v := range inlined["properties"].(map[string]interface{}) {
+					properties[k] = v
+				}
+				continue
+			}
+		}
+
+		if name == "" {
+			name = field.Name
+		}
+
+		properties[name] = g.schemaFor(field.Type)
+	}
+
+	return map[string]interface{}{
+		"type":       "object",
+		"properties": properties,
+	}
+}
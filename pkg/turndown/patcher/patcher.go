@@ -19,6 +19,7 @@ type NodePatch = func(*v1.Node) error
 type CronJobPatch = func(*v1b1.CronJob) error
 type DaemonSetPatch = func(*appsv1.DaemonSet) error
 type DeploymentPatch = func(*appsv1.Deployment) error
+type ServicePatch = func(*v1.Service) error
 
 // Error to return to the patcher if there are no updates
 var NoUpdates error = errors.New(NoUpdatesReason)
@@ -134,6 +135,37 @@ func PatchCronJob(c kubernetes.Interface, cronJob v1b1.CronJob, patch CronJobPat
 	return c.BatchV1beta1().CronJobs(ns).Patch(name, types.MergePatchType, p)
 }
 
+//
+func PatchService(c kubernetes.Interface, service v1.Service, patch ServicePatch) (*v1.Service, error) {
+	ns, name := service.Namespace, service.Name
+
+	oldData, err := json.Marshal(service)
+	if err != nil {
+		return nil, err
+	}
+
+	err = patch(&service)
+	if err != nil {
+		if IsNoUpdates(err) {
+			return &service, nil
+		}
+
+		return nil, err
+	}
+
+	newData, err := json.Marshal(service)
+	if err != nil {
+		return nil, err
+	}
+
+	p, err := strategicpatch.CreateTwoWayMergePatch(oldData, newData, service)
+	if err != nil {
+		return nil, err
+	}
+
+	return c.CoreV1().Services(ns).Patch(name, types.MergePatchType, p)
+}
+
 //
 func UpdateNodeLabel(c kubernetes.Interface, node v1.Node, labelKey string, labelValue string) (*v1.Node, error) {
 	return PatchNode(c, node, func(n *v1.Node) error {
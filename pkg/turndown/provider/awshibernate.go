@@ -0,0 +1,260 @@
+package provider
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/autoscaling"
+	"github.com/aws/aws-sdk-go/service/ec2"
+
+	"k8s.io/apimachinery/pkg/util/wait"
+)
+
+const (
+	// Tag set on an AutoScalingGroup to hibernate the instances during turndown rather than terminate them
+	AWSNodeGroupHibernateKey = "cluster.turndown.hibernate"
+
+	// The maximum number of instances accepted by a single EnterStandby or ExitStandby request
+	awsStandbyBatchSize = 20
+)
+
+// IsHibernateNodePool returns true if the NodePool is tagged to hibernate its instances during turndown.
+func IsHibernateNodePool(np NodePool) bool {
+	return strings.EqualFold(np.Tags()[AWSNodeGroupHibernateKey], "true")
+}
+
+// Hibernates the AutoScalingGroup backing the NodePool. All in-service instances are moved to Standby, then
+// stopped, preserving their disks. The previous range is stored in a tag on the group, unless a previous
+// attempt already stored it.
+func (p *AWSProvider) hibernateNodePool(np NodePool) error {
+	name := np.Name()
+
+	// Store the range before any changes, so the group can be restored after a failure. A group which
+	// is already in standby keeps the range stored by the first attempt.
+	err := p.storePreviousTag(np)
+	if err != nil {
+		return err
+	}
+
+	// Lower the minimum so that the desired capacity can be decremented as instances enter standby
+	_, err = p.clusterManager.UpdateAutoScalingGroup(&autoscaling.UpdateAutoScalingGroupInput{
+		AutoScalingGroupName: aws.String(name),
+		MinSize:              aws.Int64(0),
+	})
+	if err != nil {
+		p.log.Err("Updating AutoScalingGroup: %s", err.Error())
+		return err
+	}
+
+	asg, err := p.describeGroup(name)
+	if err != nil {
+		return err
+	}
+
+	instanceIDs := instancesInState(asg, autoscaling.LifecycleStateInService)
+	p.log.Log("Moving %d instances in %s to Standby", len(instanceIDs), name)
+
+	for _, batch := range batchInstanceIDs(instanceIDs, awsStandbyBatchSize) {
+		_, err := p.clusterManager.EnterStandby(&autoscaling.EnterStandbyInput{
+			AutoScalingGroupName:           aws.String(name),
+			InstanceIds:                    aws.StringSlice(batch),
+			ShouldDecrementDesiredCapacity: aws.Bool(true),
+		})
+		if err != nil {
+			p.log.Err("Entering Standby: %s", err.Error())
+			return err
+		}
+	}
+
+	err = p.waitForInstancesInState(name, instanceIDs, autoscaling.LifecycleStateStandby)
+	if err != nil {
+		return err
+	}
+
+	// Pin the group to 0 so no new instances are launched while hibernating
+	_, err = p.clusterManager.UpdateAutoScalingGroup(&autoscaling.UpdateAutoScalingGroupInput{
+		AutoScalingGroupName: aws.String(name),
+		MinSize:              aws.Int64(0),
+		MaxSize:              aws.Int64(0),
+		DesiredCapacity:      aws.Int64(0),
+	})
+	if err != nil {
+		p.log.Err("Updating AutoScalingGroup: %s", err.Error())
+		return err
+	}
+
+	if len(instanceIDs) == 0 {
+		return nil
+	}
+
+	p.log.Log("Stopping %d instances in %s", len(instanceIDs), name)
+	_, err = p.ec2.StopInstances(&ec2.StopInstancesInput{
+		InstanceIds: aws.StringSlice(instanceIDs),
+	})
+	if err != nil {
+		p.log.Err("Stopping Instances: %s", err.Error())
+		return err
+	}
+
+	return nil
+}
+
+// Wakes the AutoScalingGroup backing the NodePool from hibernation. All instances in Standby are started and
+// returned to service, then the group is restored to the provided range.
+func (p *AWSProvider) wakeNodePool(np NodePool, min, max, count int64) error {
+	name := np.Name()
+
+	asg, err := p.describeGroup(name)
+	if err != nil {
+		return err
+	}
+
+	instanceIDs := instancesInState(asg, autoscaling.LifecycleStateStandby)
+
+	// Restore the maximum, so the desired capacity can be incremented as instances exit standby
+	_, err = p.clusterManager.UpdateAutoScalingGroup(&autoscaling.UpdateAutoScalingGroupInput{
+		AutoScalingGroupName: aws.String(name),
+		MinSize:              aws.Int64(0),
+		MaxSize:              aws.Int64(maxInt64(max, int64(len(instanceIDs)))),
+		DesiredCapacity:      aws.Int64(0),
+	})
+	if err != nil {
+		p.log.Err("Updating AutoScalingGroup: %s", err.Error())
+		return err
+	}
+
+	if len(instanceIDs) > 0 {
+		p.log.Log("Starting %d instances in %s", len(instanceIDs), name)
+
+		_, err = p.ec2.StartInstances(&ec2.StartInstancesInput{
+			InstanceIds: aws.StringSlice(instanceIDs),
+		})
+		if err != nil {
+			p.log.Err("Starting Instances: %s", err.Error())
+			return err
+		}
+
+		err = p.ec2.WaitUntilInstanceRunning(&ec2.DescribeInstancesInput{
+			InstanceIds: aws.StringSlice(instanceIDs),
+		})
+		if err != nil {
+			p.log.Err("Waiting for Instances: %s", err.Error())
+			return err
+		}
+
+		for _, batch := range batchInstanceIDs(instanceIDs, awsStandbyBatchSize) {
+			_, err := p.clusterManager.ExitStandby(&autoscaling.ExitStandbyInput{
+				AutoScalingGroupName: aws.String(name),
+				InstanceIds:          aws.StringSlice(batch),
+			})
+			if err != nil {
+				p.log.Err("Exiting Standby: %s", err.Error())
+				return err
+			}
+		}
+	}
+
+	// Instances which were not hibernated will be launched by the group
+	return p.resetNodePoolSize(np, min, max, count)
+}
+
+// Creates or updates the tag containing the previous range on the AutoScalingGroup
+func (p *AWSProvider) createPreviousTag(np NodePool, nodeRange *string) error {
+	_, err := p.clusterManager.CreateOrUpdateTags(&autoscaling.CreateOrUpdateTagsInput{
+		Tags: []*autoscaling.Tag{
+			&autoscaling.Tag{
+				ResourceId:        aws.String(np.Name()),
+				ResourceType:      aws.String(AutoScalingGroupResourceType),
+				Key:               aws.String(AWSNodeGroupPreviousKey),
+				Value:             nodeRange,
+				PropagateAtLaunch: aws.Bool(false),
+			},
+		},
+	})
+	if err != nil {
+		p.log.Err("Creating or Updating Tags: %s", err.Error())
+		return err
+	}
+
+	np.Tags()[AWSNodeGroupPreviousKey] = aws.StringValue(nodeRange)
+	return nil
+}
+
+// Loads the current state of a single AutoScalingGroup
+func (p *AWSProvider) describeGroup(name string) (*autoscaling.Group, error) {
+	res, err := p.clusterManager.DescribeAutoScalingGroups(&autoscaling.DescribeAutoScalingGroupsInput{
+		AutoScalingGroupNames: []*string{aws.String(name)},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if len(res.AutoScalingGroups) == 0 {
+		return nil, fmt.Errorf("Failed to locate AutoScalingGroup: %s", name)
+	}
+
+	return res.AutoScalingGroups[0], nil
+}
+
+// Waits until all of the instances in the AutoScalingGroup have reached the lifecycle state
+func (p *AWSProvider) waitForInstancesInState(name string, instanceIDs []string, state string) error {
+	if len(instanceIDs) == 0 {
+		return nil
+	}
+
+	return wait.PollImmediate(10*time.Second, 10*time.Minute, func() (bool, error) {
+		asg, err := p.describeGroup(name)
+		if err != nil {
+			return false, err
+		}
+
+		inState := make(map[string]bool)
+		for _, id := range instancesInState(asg, state) {
+			inState[id] = true
+		}
+
+		for _, id := range instanceIDs {
+			if !inState[id] {
+				return false, nil
+			}
+		}
+
+		return true, nil
+	})
+}
+
+// Returns the instance ids in the AutoScalingGroup in a specific lifecycle state
+func instancesInState(asg *autoscaling.Group, state string) []string {
+	ids := []string{}
+	for _, instance := range asg.Instances {
+		if aws.StringValue(instance.LifecycleState) == state {
+			ids = append(ids, aws.StringValue(instance.InstanceId))
+		}
+	}
+
+	return ids
+}
+
+// Splits the instance ids into batches no larger than size
+func batchInstanceIDs(instanceIDs []string, size int) [][]string {
+	batches := [][]string{}
+	for size < len(instanceIDs) {
+		instanceIDs, batches = instanceIDs[size:], append(batches, instanceIDs[:size])
+	}
+
+	if len(instanceIDs) > 0 {
+		batches = append(batches, instanceIDs)
+	}
+
+	return batches
+}
+
+func maxInt64(a, b int64) int64 {
+	if a > b {
+		return a
+	}
+
+	return b
+}
@@ -0,0 +1,190 @@
+package provider
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/kubecost/cluster-turndown/pkg/logging"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/credentials"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/autoscaling"
+	"github.com/aws/aws-sdk-go/service/ec2"
+)
+
+// AutoScalingGroup held by the testAWSAPI
+type testAWSGroup struct {
+	min, max, desired int64
+	instances         map[string]string
+	tags              map[string]string
+}
+
+// Stub of the AutoScaling and EC2 query APIs holding the AutoScalingGroups of a single cluster
+type testAWSAPI struct {
+	lock    sync.Mutex
+	groups  map[string]*testAWSGroup
+	stopped []string
+}
+
+func (api *testAWSAPI) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	api.lock.Lock()
+	defer api.lock.Unlock()
+
+	r.ParseForm()
+	action := r.Form.Get("Action")
+	group := api.groups[r.Form.Get("AutoScalingGroupName")]
+
+	var result string
+	switch action {
+	case "DescribeAutoScalingGroups":
+		result = api.describeGroups(r.Form.Get("AutoScalingGroupNames.member.1"))
+
+	case "UpdateAutoScalingGroup":
+		if v := r.Form.Get("MinSize"); v != "" {
+			group.min, _ = strconv.ParseInt(v, 10, 64)
+		}
+		if v := r.Form.Get("MaxSize"); v != "" {
+			group.max, _ = strconv.ParseInt(v, 10, 64)
+		}
+		if v := r.Form.Get("DesiredCapacity"); v != "" {
+			group.desired, _ = strconv.ParseInt(v, 10, 64)
+		}
+
+	case "CreateOrUpdateTags":
+		for i := 1; r.Form.Get(fmt.Sprintf("Tags.member.%d.Key", i)) != ""; i++ {
+			name := r.Form.Get(fmt.Sprintf("Tags.member.%d.ResourceId", i))
+			key := r.Form.Get(fmt.Sprintf("Tags.member.%d.Key", i))
+			api.groups[name].tags[key] = r.Form.Get(fmt.Sprintf("Tags.member.%d.Value", i))
+		}
+
+	case "SuspendProcesses":
+
+	case "EnterStandby":
+		for i := 1; r.Form.Get(fmt.Sprintf("InstanceIds.member.%d", i)) != ""; i++ {
+			group.instances[r.Form.Get(fmt.Sprintf("InstanceIds.member.%d", i))] = autoscaling.LifecycleStateStandby
+			group.desired--
+		}
+
+	case "StopInstances":
+		for i := 1; r.Form.Get(fmt.Sprintf("InstanceId.%d", i)) != ""; i++ {
+			api.stopped = append(api.stopped, r.Form.Get(fmt.Sprintf("InstanceId.%d", i)))
+		}
+		fmt.Fprintf(w, `<StopInstancesResponse><requestId>test</requestId><instancesSet/></StopInstancesResponse>`)
+		return
+
+	default:
+		w.WriteHeader(http.StatusBadRequest)
+		fmt.Fprintf(w, `<ErrorResponse><Error><Code>InvalidAction</Code><Message>%s</Message></Error></ErrorResponse>`, action)
+		return
+	}
+
+	fmt.Fprintf(w, `<%sResponse><%sResult>%s</%sResult></%sResponse>`, action, action, result, action, action)
+}
+
+func (api *testAWSAPI) describeGroups(name string) string {
+	var b strings.Builder
+	b.WriteString("<AutoScalingGroups>")
+	for groupName, group := range api.groups {
+		if name != "" && name != groupName {
+			continue
+		}
+
+		fmt.Fprintf(&b, "<member><AutoScalingGroupName>%s</AutoScalingGroupName><MinSize>%d</MinSize><MaxSize>%d</MaxSize><DesiredCapacity>%d</DesiredCapacity>",
+			groupName, group.min, group.max, group.desired)
+
+		b.WriteString("<Instances>")
+		for id, state := range group.instances {
+			fmt.Fprintf(&b, "<member><InstanceId>%s</InstanceId><LifecycleState>%s</LifecycleState></member>", id, state)
+		}
+		b.WriteString("</Instances><Tags>")
+		for key, value := range group.tags {
+			fmt.Fprintf(&b, "<member><ResourceId>%s</ResourceId><Key>%s</Key><Value>%s</Value></member>", groupName, key, value)
+		}
+		b.WriteString("</Tags></member>")
+	}
+	b.WriteString("</AutoScalingGroups>")
+
+	return b.String()
+}
+
+func (api *testAWSAPI) group(name string) testAWSGroup {
+	api.lock.Lock()
+	defer api.lock.Unlock()
+
+	return *api.groups[name]
+}
+
+// Creates an AWSProvider backed by a stub API with a "workers" group of 2 instances, which is tagged to
+// hibernate. The returned server must be closed.
+func newTestAWSProvider() (*AWSProvider, *testAWSAPI, *httptest.Server) {
+	api := &testAWSAPI{
+		groups: map[string]*testAWSGroup{
+			"workers": {
+				min:     1,
+				max:     3,
+				desired: 2,
+				instances: map[string]string{
+					"i-1": autoscaling.LifecycleStateInService,
+					"i-2": autoscaling.LifecycleStateInService,
+				},
+				tags: map[string]string{
+					AWSNodeGroupHibernateKey: "true",
+				},
+			},
+		},
+	}
+
+	server := httptest.NewServer(api)
+
+	sess := session.New(aws.NewConfig().
+		WithEndpoint(server.URL).
+		WithRegion("us-east-1").
+		WithCredentials(credentials.NewStaticCredentials("test-id", "test-secret", "")))
+
+	return &AWSProvider{
+		kubernetes:     newTestKubernetes(),
+		clusterManager: autoscaling.New(sess),
+		ec2:            ec2.New(sess),
+		log:            logging.NamedLogger("AWSProvider"),
+	}, api, server
+}
+
+func TestAWSProviderHibernateTwiceKeepsRange(t *testing.T) {
+	p, api, server := newTestAWSProvider()
+	defer server.Close()
+
+	pools := testNodePoolsByName(t, p)
+	results := p.SetNodePoolSizes([]NodePool{pools["workers"]}, 0)
+	if err := results.Err(); err != nil {
+		t.Fatalf("Failed to hibernate: %s", err.Error())
+	}
+
+	workers := api.group("workers")
+	if workers.min != 0 || workers.max != 0 || workers.desired != 0 {
+		t.Errorf("Expected workers pinned to 0, got %d/%d/%d", workers.min, workers.max, workers.desired)
+	}
+	if workers.tags[AWSNodeGroupPreviousKey] != "1/3/2" {
+		t.Errorf("Expected previous range 1/3/2, got %q", workers.tags[AWSNodeGroupPreviousKey])
+	}
+	if len(api.stopped) != 2 {
+		t.Errorf("Expected 2 stopped instances, got %v", api.stopped)
+	}
+
+	// Hibernating the group again, ie: on a retried scale down, must keep the original range rather
+	// than recording the hibernated group
+	pools = testNodePoolsByName(t, p)
+	results = p.SetNodePoolSizes([]NodePool{pools["workers"]}, 0)
+	if err := results.Err(); err != nil {
+		t.Fatalf("Failed to hibernate again: %s", err.Error())
+	}
+
+	if previous := api.group("workers").tags[AWSNodeGroupPreviousKey]; previous != "1/3/2" {
+		t.Errorf("Expected previous range 1/3/2 after hibernating again, got %q", previous)
+	}
+}
@@ -0,0 +1,126 @@
+package provider
+
+import (
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/autoscaling"
+)
+
+const (
+	// Tag used to store the scaling processes suspended by turndown on an AutoScalingGroup
+	AWSNodeGroupSuspendedKey = "cluster.turndown.suspended"
+)
+
+var (
+	// The scaling processes which could resize or replace instances in an AutoScalingGroup while turned down
+	awsTurndownProcesses = []string{
+		"AZRebalance",
+		"HealthCheck",
+		"ReplaceUnhealthy",
+		"AlarmNotification",
+		"ScheduledActions",
+	}
+)
+
+// Suspends the scaling processes on the AutoScalingGroup backing the NodePool which are not already suspended,
+// and stores the processes turndown suspended in a tag on the group.
+func (p *AWSProvider) suspendProcesses(np NodePool) error {
+	// Processes were suspended on a previous attempt
+	if _, ok := np.Tags()[AWSNodeGroupSuspendedKey]; ok {
+		return nil
+	}
+
+	name := np.Name()
+	asg, err := p.describeGroup(name)
+	if err != nil {
+		return err
+	}
+
+	suspended := make(map[string]bool)
+	for _, process := range asg.SuspendedProcesses {
+		suspended[aws.StringValue(process.ProcessName)] = true
+	}
+
+	processes := []string{}
+	for _, process := range awsTurndownProcesses {
+		if !suspended[process] {
+			processes = append(processes, process)
+		}
+	}
+
+	if len(processes) == 0 {
+		return nil
+	}
+
+	p.log.Log("Suspending processes for %s: %s", name, strings.Join(processes, ", "))
+
+	_, err = p.clusterManager.SuspendProcesses(&autoscaling.ScalingProcessQuery{
+		AutoScalingGroupName: aws.String(name),
+		ScalingProcesses:     aws.StringSlice(processes),
+	})
+	if err != nil {
+		p.log.Err("Suspending Processes: %s", err.Error())
+		return err
+	}
+
+	value := strings.Join(processes, ",")
+	_, err = p.clusterManager.CreateOrUpdateTags(&autoscaling.CreateOrUpdateTagsInput{
+		Tags: []*autoscaling.Tag{
+			&autoscaling.Tag{
+				ResourceId:        aws.String(name),
+				ResourceType:      aws.String(AutoScalingGroupResourceType),
+				Key:               aws.String(AWSNodeGroupSuspendedKey),
+				Value:             aws.String(value),
+				PropagateAtLaunch: aws.Bool(false),
+			},
+		},
+	})
+	if err != nil {
+		p.log.Err("Creating or Updating Tags: %s", err.Error())
+		return err
+	}
+
+	np.Tags()[AWSNodeGroupSuspendedKey] = value
+	return nil
+}
+
+// Resumes the scaling processes suspended by turndown on the AutoScalingGroup backing the NodePool. Processes
+// which were suspended prior to turndown remain suspended.
+func (p *AWSProvider) resumeProcesses(np NodePool) error {
+	value, ok := np.Tags()[AWSNodeGroupSuspendedKey]
+	if !ok {
+		return nil
+	}
+
+	name := np.Name()
+	if value != "" {
+		p.log.Log("Resuming processes for %s: %s", name, strings.Replace(value, ",", ", ", -1))
+
+		_, err := p.clusterManager.ResumeProcesses(&autoscaling.ScalingProcessQuery{
+			AutoScalingGroupName: aws.String(name),
+			ScalingProcesses:     aws.StringSlice(strings.Split(value, ",")),
+		})
+		if err != nil {
+			p.log.Err("Resuming Processes: %s", err.Error())
+			return err
+		}
+	}
+
+	_, err := p.clusterManager.DeleteTags(&autoscaling.DeleteTagsInput{
+		Tags: []*autoscaling.Tag{
+			&autoscaling.Tag{
+				ResourceId:   aws.String(name),
+				ResourceType: aws.String(AutoScalingGroupResourceType),
+				Key:          aws.String(AWSNodeGroupSuspendedKey),
+			},
+		},
+	})
+	if err != nil {
+		p.log.Err("Deleting Tags: %s", err.Error())
+		return err
+	}
+
+	delete(np.Tags(), AWSNodeGroupSuspendedKey)
+	return nil
+}
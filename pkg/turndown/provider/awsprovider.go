@@ -21,6 +21,7 @@ import (
 	"github.com/aws/aws-sdk-go/aws/credentials"
 	"github.com/aws/aws-sdk-go/aws/session"
 	"github.com/aws/aws-sdk-go/service/autoscaling"
+	"github.com/aws/aws-sdk-go/service/ec2"
 )
 
 const (
@@ -59,19 +60,28 @@ type AccessKey struct {
 type AWSProvider struct {
 	kubernetes     kubernetes.Interface
 	clusterManager *autoscaling.AutoScaling
+	ec2            *ec2.EC2
 	log            logging.NamedLogger
 }
 
 func NewAWSProvider(kubernetes kubernetes.Interface) ComputeProvider {
 	region := findAWSRegion(kubernetes)
-	clusterManager, err := newAWSClusterManager(region)
+	sess, err := newAWSSession(region)
 	if err != nil {
 		klog.V(1).Infof("Failed to load service account.")
 	}
 
+	var clusterManager *autoscaling.AutoScaling
+	var ec2Client *ec2.EC2
+	if sess != nil {
+		clusterManager = autoscaling.New(sess)
+		ec2Client = ec2.New(sess)
+	}
+
 	return &AWSProvider{
 		kubernetes:     kubernetes,
 		clusterManager: clusterManager,
+		ec2:            ec2Client,
 		log:            logging.NamedLogger("AWSProvider"),
 	}
 }
@@ -133,107 +143,203 @@ func (p *AWSProvider) GetNodePools() ([]NodePool, error) {
 	return pools, nil
 }
 
-func (p *AWSProvider) SetNodePoolSizes(nodePools []NodePool, size int32) error {
-	if len(nodePools) == 0 {
-		return nil
+func (p *AWSProvider) SetNodePoolSizes(nodePools []NodePool, size int32) NodePoolResults {
+	results := NodePoolResults{}
+
+	for _, np := range nodePools {
+		var err error
+
+		// Hibernation only applies to turning down the group. Scaling processes are suspended first, so
+		// the group is not rebalanced or replaced behind turndown's back.
+		if size == 0 {
+			err = p.suspendProcesses(np)
+			if err == nil && IsHibernateNodePool(np) {
+				err = p.hibernateNodePool(np)
+			} else if err == nil {
+				err = p.setNodePoolSize(np, size)
+			}
+		} else {
+			err = p.setNodePoolSize(np, size)
+		}
+
+		results = append(results, NewNodePoolResult(np, size, np.NodeCount(), err))
 	}
 
+	return results
+}
+
+// Updates the AutoScalingGroup backing the NodePool to the provided size and stores the previous
+// range in a tag on the group
+func (p *AWSProvider) setNodePoolSize(np NodePool, size int32) error {
 	sz := int64(size)
 
-	for _, np := range nodePools {
-		min, max, count := np.MinNodes(), np.MaxNodes(), np.NodeCount()
+	err := p.storePreviousTag(np)
+	if err != nil {
+		return err
+	}
 
-		update := &autoscaling.UpdateAutoScalingGroupInput{
-			AutoScalingGroupName: aws.String(np.Name()),
-			MinSize:              aws.Int64(sz),
-			MaxSize:              aws.Int64(sz),
-			DesiredCapacity:      aws.Int64(sz),
-		}
+	update := &autoscaling.UpdateAutoScalingGroupInput{
+		AutoScalingGroupName: aws.String(np.Name()),
+		MinSize:              aws.Int64(sz),
+		MaxSize:              aws.Int64(sz),
+		DesiredCapacity:      aws.Int64(sz),
+	}
 
-		_, err := p.clusterManager.UpdateAutoScalingGroup(update)
-		if err != nil {
-			p.log.Err("Updating AutoScalingGroup: %s", err.Error())
-			return err
-		}
+	_, err = p.clusterManager.UpdateAutoScalingGroup(update)
+	if err != nil {
+		p.log.Err("Updating AutoScalingGroup: %s", err.Error())
+	}
 
-		nodeRange := flatRange(min, max, count)
-		tagsIn := &autoscaling.CreateOrUpdateTagsInput{
-			Tags: []*autoscaling.Tag{
-				&autoscaling.Tag{
-					ResourceId:        aws.String(np.Name()),
-					ResourceType:      aws.String(AutoScalingGroupResourceType),
-					Key:               aws.String(AWSNodeGroupPreviousKey),
-					Value:             nodeRange,
-					PropagateAtLaunch: aws.Bool(false),
-				},
-			},
-		}
+	return err
+}
 
-		_, err = p.clusterManager.CreateOrUpdateTags(tagsIn)
-		if err != nil {
-			p.log.Err("Creating or Updating Tags: %s", err.Error())
+// Stores the current range of the AutoScalingGroup in a tag on the group, unless a previous attempt
+// already stored it. The group may already be scaled down, so its current range must not replace the
+// stored one.
+func (p *AWSProvider) storePreviousTag(np NodePool) error {
+	if _, ok := np.Tags()[AWSNodeGroupPreviousKey]; ok {
+		return nil
+	}
+
+	return p.createPreviousTag(np, flatRange(np.MinNodes(), np.MaxNodes(), np.NodeCount()))
+}
+
+// RemoveNodes terminates the instances backing the nodes, decrementing the desired capacity of the
+// AutoScalingGroup, so the group does not replace them or choose other instances to terminate.
+func (p *AWSProvider) RemoveNodes(np NodePool, nodes []v1.Node) *NodePoolResult {
+	count := np.NodeCount()
+
+	size := count - int32(len(nodes))
+	if size < 0 {
+		size = 0
+	}
+
+	err := p.storePreviousTag(np)
+	if err != nil {
+		return NewNodePoolResult(np, size, count, err)
+	}
+
+	err = p.suspendProcesses(np)
+	if err != nil {
+		return NewNodePoolResult(np, size, count, err)
+	}
+
+	// The desired capacity cannot be decremented below the minimum
+	_, err = p.clusterManager.UpdateAutoScalingGroup(&autoscaling.UpdateAutoScalingGroupInput{
+		AutoScalingGroupName: aws.String(np.Name()),
+		MinSize:              aws.Int64(int64(size)),
+	})
+	if err != nil {
+		p.log.Err("Updating AutoScalingGroup: %s", err.Error())
+		return NewNodePoolResult(np, size, count, err)
+	}
 
-			return err
+	for _, node := range nodes {
+		_, instanceID := p.instanceInfoFor(&node)
+
+		p.log.Log("Terminating Instance: %s for Node: %s in AutoScalingGroup: %s", instanceID, node.Name, np.Name())
+		_, err := p.clusterManager.TerminateInstanceInAutoScalingGroup(&autoscaling.TerminateInstanceInAutoScalingGroupInput{
+			InstanceId:                     aws.String(instanceID),
+			ShouldDecrementDesiredCapacity: aws.Bool(true),
+		})
+		if err != nil {
+			p.log.Err("Terminating Instance: %s", err.Error())
+			return NewNodePoolResult(np, size, count, err)
 		}
+	}
 
-		np.Tags()[AWSNodeGroupPreviousKey] = aws.StringValue(nodeRange)
+	// Stop the group from scaling back up while turned down
+	_, err = p.clusterManager.UpdateAutoScalingGroup(&autoscaling.UpdateAutoScalingGroupInput{
+		AutoScalingGroupName: aws.String(np.Name()),
+		MaxSize:              aws.Int64(int64(size)),
+	})
+	if err != nil {
+		p.log.Err("Updating AutoScalingGroup: %s", err.Error())
 	}
 
-	return nil
+	return NewNodePoolResult(np, size, count, err)
 }
 
-func (p *AWSProvider) ResetNodePoolSizes(nodePools []NodePool) error {
-	if len(nodePools) == 0 {
-		return nil
-	}
+func (p *AWSProvider) ResetNodePoolSizes(nodePools []NodePool) NodePoolResults {
+	results := NodePoolResults{}
 
 	for _, np := range nodePools {
 		tags := np.Tags()
 		rangeTag, ok := tags[AWSNodeGroupPreviousKey]
 		if !ok {
 			p.log.Err("Failed to locate tag: %s for NodePool: %s", AWSNodeGroupPreviousKey, np.Name())
+			results = append(results, &NodePoolResult{
+				NodePool:     np,
+				PreviousSize: np.NodeCount(),
+				Outcome:      NodePoolSkipped,
+				Error:        fmt.Errorf("Missing tag: %s", AWSNodeGroupPreviousKey),
+			})
 			continue
 		}
 
 		min, max, count := expandRange(rangeTag)
 		if count < 0 {
 			p.log.Err("Failed to parse range used to resize node pool.")
+			results = append(results, &NodePoolResult{
+				NodePool:     np,
+				PreviousSize: np.NodeCount(),
+				Outcome:      NodePoolSkipped,
+				Error:        fmt.Errorf("Failed to parse range: %s", rangeTag),
+			})
 			continue
 		}
 
-		update := &autoscaling.UpdateAutoScalingGroupInput{
-			AutoScalingGroupName: aws.String(np.Name()),
-			MinSize:              aws.Int64(min),
-			MaxSize:              aws.Int64(max),
-			DesiredCapacity:      aws.Int64(count),
+		var err error
+		if IsHibernateNodePool(np) {
+			err = p.wakeNodePool(np, min, max, count)
+		} else {
+			err = p.resetNodePoolSize(np, min, max, count)
 		}
 
-		_, err := p.clusterManager.UpdateAutoScalingGroup(update)
-		if err != nil {
-			p.log.Err("Updating AutoScalingGroup: %s", err.Error())
-			return err
+		// Processes are resumed once the group is restored, so they do not act on the resize
+		if err == nil {
+			err = p.resumeProcesses(np)
 		}
 
-		deleteTagsIn := &autoscaling.DeleteTagsInput{
-			Tags: []*autoscaling.Tag{
-				&autoscaling.Tag{
-					ResourceId:   aws.String(np.Name()),
-					ResourceType: aws.String(AutoScalingGroupResourceType),
-					Key:          aws.String(AWSNodeGroupPreviousKey),
-				},
-			},
-		}
+		results = append(results, NewNodePoolResult(np, int32(count), np.NodeCount(), err))
+	}
 
-		_, err = p.clusterManager.DeleteTags(deleteTagsIn)
-		if err != nil {
-			p.log.Err("Deleting Tags: %s", err.Error())
+	return results
+}
 
-			return err
-		}
+// Restores the AutoScalingGroup backing the NodePool to the provided range and removes the
+// previous range tag from the group
+func (p *AWSProvider) resetNodePoolSize(np NodePool, min, max, count int64) error {
+	update := &autoscaling.UpdateAutoScalingGroupInput{
+		AutoScalingGroupName: aws.String(np.Name()),
+		MinSize:              aws.Int64(min),
+		MaxSize:              aws.Int64(max),
+		DesiredCapacity:      aws.Int64(count),
+	}
+
+	_, err := p.clusterManager.UpdateAutoScalingGroup(update)
+	if err != nil {
+		p.log.Err("Updating AutoScalingGroup: %s", err.Error())
+		return err
+	}
 
-		delete(tags, AWSNodeGroupPreviousKey)
+	deleteTagsIn := &autoscaling.DeleteTagsInput{
+		Tags: []*autoscaling.Tag{
+			&autoscaling.Tag{
+				ResourceId:   aws.String(np.Name()),
+				ResourceType: aws.String(AutoScalingGroupResourceType),
+				Key:          aws.String(AWSNodeGroupPreviousKey),
+			},
+		},
+	}
+
+	_, err = p.clusterManager.DeleteTags(deleteTagsIn)
+	if err != nil {
+		p.log.Err("Deleting Tags: %s", err.Error())
+		return err
 	}
 
+	delete(np.Tags(), AWSNodeGroupPreviousKey)
 	return nil
 }
 
@@ -245,7 +351,7 @@ func (p *AWSProvider) instanceInfoFor(node *v1.Node) (zone string, instanceID st
 	return splitted[0], splitted[1]
 }
 
-func newAWSClusterManager(region string) (*autoscaling.AutoScaling, error) {
+func newAWSSession(region string) (*session.Session, error) {
 	if !file.FileExists(AWSAccessKey) {
 		return nil, fmt.Errorf("Failed to locate service account file: %s", AWSAccessKey)
 	}
@@ -267,9 +373,8 @@ func newAWSClusterManager(region string) (*autoscaling.AutoScaling, error) {
 	c := aws.NewConfig().
 		WithCredentials(credentials.NewEnvCredentials()).
 		WithRegion(region)
-	clusterManager := autoscaling.New(session.New(c))
 
-	return clusterManager, nil
+	return session.New(c), nil
 }
 
 func tagsToMap(tags []*autoscaling.TagDescription) map[string]string {
@@ -0,0 +1,377 @@
+package provider
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/kubecost/cluster-turndown/pkg/logging"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/kubernetes"
+)
+
+const (
+	CAPIGroup    = "cluster.x-k8s.io"
+	CAPIExpGroup = "exp.cluster.x-k8s.io"
+
+	CAPIMachineDeploymentKind     = "MachineDeployment"
+	CAPIMachinePoolKind           = "MachinePool"
+	CAPIMachineDeploymentResource = "machinedeployments"
+	CAPIMachinePoolResource       = "machinepools"
+	CAPIMachineResource           = "machines"
+
+	CAPIClusterNameLabel           = "cluster.x-k8s.io/cluster-name"
+	CAPIClusterNameAnnotation      = "cluster.x-k8s.io/cluster-name"
+	CAPIDeploymentNameLabel        = "cluster.x-k8s.io/deployment-name"
+	CAPIMachineAnnotation          = "cluster.x-k8s.io/machine"
+	CAPIClusterNamespaceAnnotation = "cluster.x-k8s.io/cluster-namespace"
+	CAPIOwnerKindAnnotation        = "cluster.x-k8s.io/owner-kind"
+	CAPIOwnerNameAnnotation        = "cluster.x-k8s.io/owner-name"
+	CAPIAutoscalerMinAnnotation    = "cluster.x-k8s.io/cluster-api-autoscaler-node-group-min-size"
+	CAPIAutoscalerMaxAnnotation    = "cluster.x-k8s.io/cluster-api-autoscaler-node-group-max-size"
+
+	// Annotation used to store the replicas of a MachineDeployment or MachinePool prior to turndown
+	CAPIPreviousReplicasAnnotation = "cluster.turndown.previous"
+)
+
+// CAPI NodePool based on a MachineDeployment or MachinePool
+type CAPINodePool struct {
+	kind        string
+	namespace   string
+	name        string
+	clusterName string
+	zone        string
+	min         int32
+	max         int32
+	count       int32
+	autoscaling bool
+	tags        map[string]string
+}
+
+func (np *CAPINodePool) Name() string            { return capiPoolID(np.kind, np.namespace, np.name) }
+func (np *CAPINodePool) Project() string         { return np.namespace }
+func (np *CAPINodePool) Zone() string            { return np.zone }
+func (np *CAPINodePool) ClusterID() string       { return np.clusterName }
+func (np *CAPINodePool) MinNodes() int32         { return np.min }
+func (np *CAPINodePool) MaxNodes() int32         { return np.max }
+func (np *CAPINodePool) NodeCount() int32        { return np.count }
+func (np *CAPINodePool) AutoScaling() bool       { return np.autoscaling }
+func (np *CAPINodePool) Tags() map[string]string { return np.tags }
+
+// ComputeProvider for clusters managed by Cluster API. The Cluster API resources are expected to
+// exist in the same cluster turndown runs in (a self-hosted management cluster). Only the
+// MachineDeployments and MachinePools of that cluster are turned down, which is identified by the
+// Cluster API annotations on its nodes.
+type CAPIProvider struct {
+	kubernetes kubernetes.Interface
+	dynamic    dynamic.Interface
+	resources  map[string]schema.GroupVersionResource
+	machines   schema.GroupVersionResource
+	log        logging.NamedLogger
+}
+
+// IsCAPICluster returns true if the Cluster API MachineDeployment resource is served by the cluster, and
+// the nodes of the cluster are managed by Cluster API. A management cluster which is not itself managed
+// by Cluster API, ie: an EKS cluster, serves the resources, but none of its nodes are annotated.
+func IsCAPICluster(kubernetes kubernetes.Interface) bool {
+	if _, ok := DiscoverResource(kubernetes, CAPIGroup, CAPIMachineDeploymentResource); !ok {
+		return false
+	}
+
+	nodes, err := kubernetes.CoreV1().Nodes().List(metav1.ListOptions{})
+	if err != nil {
+		return false
+	}
+
+	_, _, ok := capiClusterFor(nodes.Items)
+	return ok
+}
+
+func NewCAPIProvider(kubernetes kubernetes.Interface, dynamic dynamic.Interface) (ComputeProvider, error) {
+	resources := make(map[string]schema.GroupVersionResource)
+
+	mds, ok := DiscoverResource(kubernetes, CAPIGroup, CAPIMachineDeploymentResource)
+	if !ok {
+		return nil, fmt.Errorf("Failed to locate the %s resource in group: %s", CAPIMachineDeploymentResource, CAPIGroup)
+	}
+	resources[CAPIMachineDeploymentKind] = mds
+
+	// MachinePools are served by the experimental group in older versions of Cluster API
+	if mps, ok := DiscoverResource(kubernetes, CAPIGroup, CAPIMachinePoolResource); ok {
+		resources[CAPIMachinePoolKind] = mps
+	} else if mps, ok := DiscoverResource(kubernetes, CAPIExpGroup, CAPIMachinePoolResource); ok {
+		resources[CAPIMachinePoolKind] = mps
+	}
+
+	return &CAPIProvider{
+		kubernetes: kubernetes,
+		dynamic:    dynamic,
+		resources:  resources,
+		machines:   mds.GroupVersion().WithResource(CAPIMachineResource),
+		log:        logging.NamedLogger("CAPIProvider"),
+	}, nil
+}
+
+// Cluster API does not require a service account key, as all operations are performed through the
+// kubernetes API.
+func (p *CAPIProvider) IsServiceAccountKey() bool {
+	return true
+}
+
+func (p *CAPIProvider) IsTurndownNodePool() bool {
+	return false
+}
+
+func (p *CAPIProvider) CreateSingletonNodePool() error {
+	return fmt.Errorf("Creating a Singleton Node not supported on Cluster API!")
+}
+
+func (p *CAPIProvider) GetPoolID(node *v1.Node) string {
+	annotations := node.GetAnnotations()
+	namespace := annotations[CAPIClusterNamespaceAnnotation]
+
+	switch annotations[CAPIOwnerKindAnnotation] {
+	case CAPIMachinePoolKind:
+		return capiPoolID(CAPIMachinePoolKind, namespace, annotations[CAPIOwnerNameAnnotation])
+
+	default:
+		// Nodes owned by a MachineSet are mapped to the MachineDeployment through the Machine labels
+		machineName, ok := annotations[CAPIMachineAnnotation]
+		if !ok {
+			return ""
+		}
+
+		machine, err := p.dynamic.Resource(p.machines).Namespace(namespace).Get(machineName, metav1.GetOptions{})
+		if err != nil {
+			p.log.Err("Failed to locate Machine: %s/%s - %s", namespace, machineName, err.Error())
+			return ""
+		}
+
+		deployment, ok := machine.GetLabels()[CAPIDeploymentNameLabel]
+		if !ok {
+			return ""
+		}
+
+		return capiPoolID(CAPIMachineDeploymentKind, namespace, deployment)
+	}
+}
+
+func (p *CAPIProvider) GetNodePools() ([]NodePool, error) {
+	namespace, clusterName, err := p.currentCluster()
+	if err != nil {
+		return nil, err
+	}
+
+	pools := []NodePool{}
+
+	for _, kind := range []string{CAPIMachineDeploymentKind, CAPIMachinePoolKind} {
+		resource, ok := p.resources[kind]
+		if !ok {
+			continue
+		}
+
+		list, err := p.dynamic.Resource(resource).Namespace(namespace).List(metav1.ListOptions{})
+		if err != nil {
+			return nil, err
+		}
+
+		// A management cluster may also manage other workload clusters, which must not be turned down
+		for _, item := range list.Items {
+			pool := newCAPINodePool(kind, &item)
+			if pool.clusterName != clusterName {
+				continue
+			}
+
+			pools = append(pools, pool)
+		}
+	}
+
+	return pools, nil
+}
+
+// Determines the namespace and name of the Cluster API Cluster that turndown runs in, using the
+// annotations Cluster API sets on the nodes it manages
+func (p *CAPIProvider) currentCluster() (namespace string, clusterName string, err error) {
+	nodes, err := p.kubernetes.CoreV1().Nodes().List(metav1.ListOptions{})
+	if err != nil {
+		return "", "", err
+	}
+
+	namespace, clusterName, ok := capiClusterFor(nodes.Items)
+	if !ok {
+		return "", "", fmt.Errorf("Failed to locate a node annotated with: %s", CAPIClusterNameAnnotation)
+	}
+
+	return namespace, clusterName, nil
+}
+
+// Returns the Cluster API cluster of the first node annotated with the cluster name
+func capiClusterFor(nodes []v1.Node) (namespace string, clusterName string, ok bool) {
+	for _, node := range nodes {
+		annotations := node.GetAnnotations()
+
+		name, ok := annotations[CAPIClusterNameAnnotation]
+		if !ok || name == "" {
+			continue
+		}
+
+		return annotations[CAPIClusterNamespaceAnnotation], name, true
+	}
+
+	return "", "", false
+}
+
+func (p *CAPIProvider) SetNodePoolSizes(nodePools []NodePool, size int32) NodePoolResults {
+	results := NodePoolResults{}
+
+	for _, np := range nodePools {
+		// Replicas stored by a previous attempt are kept, as the node pool may already be scaled down
+		previous, ok := np.Tags()[CAPIPreviousReplicasAnnotation]
+		if !ok {
+			previous = fmt.Sprintf("%d", np.NodeCount())
+		}
+
+		err := p.patchReplicas(np, size, &previous)
+		if err == nil {
+			np.Tags()[CAPIPreviousReplicasAnnotation] = previous
+		}
+
+		results = append(results, NewNodePoolResult(np, size, np.NodeCount(), err))
+	}
+
+	return results
+}
+
+func (p *CAPIProvider) ResetNodePoolSizes(nodePools []NodePool) NodePoolResults {
+	results := NodePoolResults{}
+
+	for _, np := range nodePools {
+		previous, ok := np.Tags()[CAPIPreviousReplicasAnnotation]
+		if !ok {
+			p.log.Err("Failed to locate annotation: %s for NodePool: %s", CAPIPreviousReplicasAnnotation, np.Name())
+			results = append(results, &NodePoolResult{
+				NodePool:     np,
+				PreviousSize: np.NodeCount(),
+				Outcome:      NodePoolSkipped,
+				Error:        fmt.Errorf("Missing annotation: %s", CAPIPreviousReplicasAnnotation),
+			})
+			continue
+		}
+
+		count, err := strconv.ParseInt(previous, 10, 32)
+		if err != nil {
+			p.log.Err("Failed to parse replicas used to resize node pool: %s", err.Error())
+			results = append(results, &NodePoolResult{
+				NodePool:     np,
+				PreviousSize: np.NodeCount(),
+				Outcome:      NodePoolSkipped,
+				Error:        err,
+			})
+			continue
+		}
+
+		err = p.patchReplicas(np, int32(count), nil)
+		if err == nil {
+			delete(np.Tags(), CAPIPreviousReplicasAnnotation)
+		}
+
+		results = append(results, NewNodePoolResult(np, int32(count), np.NodeCount(), err))
+	}
+
+	return results
+}
+
+// Patches the replicas of the MachineDeployment or MachinePool backing the NodePool. If previous is nil, the
+// previous replicas annotation is removed, otherwise it is set to the provided value.
+func (p *CAPIProvider) patchReplicas(np NodePool, replicas int32, previous *string) error {
+	pool, ok := np.(*CAPINodePool)
+	if !ok {
+		return fmt.Errorf("NodePool: %s is not a Cluster API NodePool", np.Name())
+	}
+
+	resource, ok := p.resources[pool.kind]
+	if !ok {
+		return fmt.Errorf("Resource for kind: %s is not available", pool.kind)
+	}
+
+	patch := map[string]interface{}{
+		"metadata": map[string]interface{}{
+			"annotations": map[string]interface{}{
+				CAPIPreviousReplicasAnnotation: previous,
+			},
+		},
+		"spec": map[string]interface{}{
+			"replicas": replicas,
+		},
+	}
+
+	data, err := json.Marshal(patch)
+	if err != nil {
+		return err
+	}
+
+	p.log.Log("Resizing %s: %s/%s to %d", pool.kind, pool.namespace, pool.name, replicas)
+
+	_, err = p.dynamic.Resource(resource).Namespace(pool.namespace).Patch(pool.name, types.MergePatchType, data, metav1.PatchOptions{})
+	if err != nil {
+		p.log.Err("Patching %s: %s", pool.kind, err.Error())
+	}
+
+	return err
+}
+
+// Creates a new CAPINodePool from an unstructured MachineDeployment or MachinePool
+func newCAPINodePool(kind string, obj *unstructured.Unstructured) *CAPINodePool {
+	replicas, _, _ := unstructured.NestedInt64(obj.Object, "spec", "replicas")
+	zone, _, _ := unstructured.NestedString(obj.Object, "spec", "template", "spec", "failureDomain")
+	clusterName, _, _ := unstructured.NestedString(obj.Object, "spec", "clusterName")
+	if clusterName == "" {
+		clusterName = obj.GetLabels()[CAPIClusterNameLabel]
+	}
+
+	tags := make(map[string]string)
+	for k, v := range obj.GetAnnotations() {
+		tags[k] = v
+	}
+
+	count := int32(replicas)
+	min, max := count, count
+
+	// The cluster-autoscaler Cluster API provider uses annotations to enable autoscaling
+	minEntry, hasMin := tags[CAPIAutoscalerMinAnnotation]
+	maxEntry, hasMax := tags[CAPIAutoscalerMaxAnnotation]
+	autoscaling := hasMin && hasMax
+	if autoscaling {
+		if v, err := strconv.ParseInt(minEntry, 10, 32); err == nil {
+			min = int32(v)
+		}
+		if v, err := strconv.ParseInt(maxEntry, 10, 32); err == nil {
+			max = int32(v)
+		}
+	}
+
+	return &CAPINodePool{
+		kind:        kind,
+		namespace:   obj.GetNamespace(),
+		name:        obj.GetName(),
+		clusterName: clusterName,
+		zone:        zone,
+		min:         min,
+		max:         max,
+		count:       count,
+		autoscaling: autoscaling,
+		tags:        tags,
+	}
+}
+
+// Creates a unique NodePool identifier for a MachineDeployment or MachinePool
+func capiPoolID(kind, namespace, name string) string {
+	return fmt.Sprintf("%s/%s/%s", strings.ToLower(kind), namespace, name)
+}
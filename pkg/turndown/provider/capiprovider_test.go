@@ -0,0 +1,172 @@
+package provider
+
+import (
+	"testing"
+
+	"github.com/kubecost/cluster-turndown/pkg/logging"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+)
+
+var (
+	testMachineDeployments = schema.GroupVersionResource{Group: CAPIGroup, Version: "v1alpha3", Resource: CAPIMachineDeploymentResource}
+	testMachines           = schema.GroupVersionResource{Group: CAPIGroup, Version: "v1alpha3", Resource: CAPIMachineResource}
+)
+
+func newTestMachineDeployment(namespace, name, clusterName string, replicas int64) *unstructured.Unstructured {
+	return &unstructured.Unstructured{
+		Object: map[string]interface{}{
+			"apiVersion": testMachineDeployments.GroupVersion().String(),
+			"kind":       CAPIMachineDeploymentKind,
+			"metadata": map[string]interface{}{
+				"namespace": namespace,
+				"name":      name,
+			},
+			"spec": map[string]interface{}{
+				"clusterName": clusterName,
+				"replicas":    replicas,
+			},
+		},
+	}
+}
+
+func newTestMachine(namespace, name, deployment string) *unstructured.Unstructured {
+	return &unstructured.Unstructured{
+		Object: map[string]interface{}{
+			"apiVersion": testMachines.GroupVersion().String(),
+			"kind":       "Machine",
+			"metadata": map[string]interface{}{
+				"namespace": namespace,
+				"name":      name,
+				"labels": map[string]interface{}{
+					CAPIDeploymentNameLabel: deployment,
+				},
+			},
+		},
+	}
+}
+
+func newTestCAPINode(name, namespace, clusterName, machine string) *v1.Node {
+	return newTestNode(name, map[string]string{
+		CAPIClusterNameAnnotation:      clusterName,
+		CAPIClusterNamespaceAnnotation: namespace,
+		CAPIMachineAnnotation:          machine,
+		CAPIOwnerKindAnnotation:        "MachineSet",
+	})
+}
+
+// Creates a CAPIProvider for the "workload" cluster in the "default" namespace. The management cluster
+// also manages the "other" cluster, which must not be turned down.
+func newTestCAPIProvider() *CAPIProvider {
+	kubernetes := newTestKubernetes(newTestCAPINode("node-1", "default", "workload", "md-1-abc"))
+	dynamic := dynamicfake.NewSimpleDynamicClient(runtime.NewScheme(),
+		newTestMachineDeployment("default", "md-1", "workload", 3),
+		newTestMachineDeployment("default", "md-2", "workload", 2),
+		newTestMachineDeployment("default", "other-md", "other", 5),
+		newTestMachineDeployment("other", "md-1", "other", 4),
+		newTestMachine("default", "md-1-abc", "md-1"),
+	)
+
+	return &CAPIProvider{
+		kubernetes: kubernetes,
+		dynamic:    dynamic,
+		resources: map[string]schema.GroupVersionResource{
+			CAPIMachineDeploymentKind: testMachineDeployments,
+		},
+		machines: testMachines,
+		log:      logging.NamedLogger("CAPIProvider"),
+	}
+}
+
+func testReplicas(t *testing.T, p *CAPIProvider, namespace, name string) (int64, string) {
+	md, err := p.dynamic.Resource(testMachineDeployments).Namespace(namespace).Get(name, metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("Failed to get MachineDeployment: %s", err.Error())
+	}
+
+	replicas, _, _ := unstructured.NestedInt64(md.Object, "spec", "replicas")
+	return replicas, md.GetAnnotations()[CAPIPreviousReplicasAnnotation]
+}
+
+func TestCAPIProviderDiscoversCurrentClusterNodePools(t *testing.T) {
+	p := newTestCAPIProvider()
+
+	pools := testNodePoolsByName(t, p)
+	if len(pools) != 2 {
+		t.Fatalf("Expected 2 node pools, got %d: %v", len(pools), pools)
+	}
+
+	md1, ok := pools["machinedeployment/default/md-1"]
+	if !ok {
+		t.Fatalf("Expected node pool machinedeployment/default/md-1")
+	}
+	if md1.NodeCount() != 3 || md1.ClusterID() != "workload" {
+		t.Errorf("Expected 3 nodes in cluster workload, got %d in %s", md1.NodeCount(), md1.ClusterID())
+	}
+
+	node := newTestCAPINode("node-1", "default", "workload", "md-1-abc")
+	if id := p.GetPoolID(node); id != md1.Name() {
+		t.Errorf("Expected pool id %s, got %s", md1.Name(), id)
+	}
+}
+
+func TestCAPIProviderRequiresCurrentCluster(t *testing.T) {
+	p := newTestCAPIProvider()
+	p.kubernetes = newTestKubernetes(newTestNode("node-1", nil))
+
+	_, err := p.GetNodePools()
+	if err == nil {
+		t.Fatalf("Expected an error for nodes without Cluster API annotations")
+	}
+}
+
+func TestCAPIProviderScaleToZeroAndRestore(t *testing.T) {
+	p := newTestCAPIProvider()
+
+	pools := testNodePoolsByName(t, p)
+	md1 := pools["machinedeployment/default/md-1"]
+
+	results := p.SetNodePoolSizes([]NodePool{md1}, 0)
+	if err := results.Err(); err != nil {
+		t.Fatalf("Failed to scale down: %s", err.Error())
+	}
+
+	replicas, previous := testReplicas(t, p, "default", "md-1")
+	if replicas != 0 || previous != "3" {
+		t.Errorf("Expected 0 replicas with previous 3, got %d with previous %q", replicas, previous)
+	}
+
+	// Scaling down again must keep the original replicas rather than recording 0
+	pools = testNodePoolsByName(t, p)
+	md1 = pools["machinedeployment/default/md-1"]
+
+	results = p.SetNodePoolSizes([]NodePool{md1}, 0)
+	if err := results.Err(); err != nil {
+		t.Fatalf("Failed to scale down again: %s", err.Error())
+	}
+
+	_, previous = testReplicas(t, p, "default", "md-1")
+	if previous != "3" {
+		t.Errorf("Expected previous 3 after scaling down again, got %q", previous)
+	}
+
+	results = p.ResetNodePoolSizes([]NodePool{md1})
+	if err := results.Err(); err != nil {
+		t.Fatalf("Failed to restore: %s", err.Error())
+	}
+
+	replicas, previous = testReplicas(t, p, "default", "md-1")
+	if replicas != 3 || previous != "" {
+		t.Errorf("Expected 3 replicas without previous, got %d with previous %q", replicas, previous)
+	}
+
+	// Other clusters are untouched
+	if replicas, _ := testReplicas(t, p, "default", "other-md"); replicas != 5 {
+		t.Errorf("Expected other cluster to keep 5 replicas, got %d", replicas)
+	}
+}
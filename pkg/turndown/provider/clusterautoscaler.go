@@ -0,0 +1,149 @@
+package provider
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/kubecost/cluster-turndown/pkg/logging"
+	"github.com/kubecost/cluster-turndown/pkg/turndown/patcher"
+
+	appsv1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+const (
+	ClusterAutoscalerName = "cluster-autoscaler"
+
+	// Annotation used to store the replicas of the cluster-autoscaler deployment while paused by turndown
+	KubecostTurnDownAutoscalerReplicas = "kubecost.kubernetes.io/turn-down-autoscaler-replicas"
+)
+
+var (
+	clusterAutoscalerLabels = map[string][]string{
+		"app":                    []string{ClusterAutoscalerName},
+		"k8s-app":                []string{ClusterAutoscalerName},
+		"app.kubernetes.io/name": []string{ClusterAutoscalerName, "aws-cluster-autoscaler"},
+	}
+)
+
+// PauseClusterAutoscaler scales any cluster-autoscaler deployments to zero, storing the previous replicas in
+// an annotation, so the autoscaler cannot resize node groups while turned down.
+func PauseClusterAutoscaler(client kubernetes.Interface) error {
+	log := logging.NamedLogger("ClusterAutoscaler")
+
+	deployments, err := findClusterAutoscalers(client)
+	if err != nil {
+		return err
+	}
+
+	for _, deployment := range deployments {
+		log.Log("Pausing cluster-autoscaler: %s/%s", deployment.Namespace, deployment.Name)
+
+		_, err := patcher.PatchDeployment(client, deployment, func(d *appsv1.Deployment) error {
+			if _, ok := d.Annotations[KubecostTurnDownAutoscalerReplicas]; ok {
+				return patcher.NoUpdates
+			}
+
+			var replicas int32 = 1
+			if d.Spec.Replicas != nil {
+				replicas = *d.Spec.Replicas
+			}
+
+			if d.Annotations == nil {
+				d.Annotations = make(map[string]string)
+			}
+			d.Annotations[KubecostTurnDownAutoscalerReplicas] = fmt.Sprintf("%d", replicas)
+
+			var zero int32 = 0
+			d.Spec.Replicas = &zero
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// ResumeClusterAutoscaler restores the replicas of any cluster-autoscaler deployments paused by turndown.
+// Returns true if any deployments were resumed.
+func ResumeClusterAutoscaler(client kubernetes.Interface) (bool, error) {
+	log := logging.NamedLogger("ClusterAutoscaler")
+
+	deployments, err := findClusterAutoscalers(client)
+	if err != nil {
+		return false, err
+	}
+
+	resumed := false
+	for _, deployment := range deployments {
+		if _, ok := deployment.Annotations[KubecostTurnDownAutoscalerReplicas]; !ok {
+			continue
+		}
+
+		_, err := patcher.PatchDeployment(client, deployment, func(d *appsv1.Deployment) error {
+			entry, ok := d.Annotations[KubecostTurnDownAutoscalerReplicas]
+			if !ok {
+				return patcher.NoUpdates
+			}
+
+			replicas, err := strconv.ParseInt(entry, 10, 32)
+			if err != nil {
+				return err
+			}
+
+			log.Log("Resuming cluster-autoscaler: %s/%s", d.Namespace, d.Name)
+
+			numReplicas := int32(replicas)
+			d.Spec.Replicas = &numReplicas
+			delete(d.Annotations, KubecostTurnDownAutoscalerReplicas)
+			return nil
+		})
+		if err != nil {
+			return resumed, err
+		}
+		resumed = true
+	}
+
+	return resumed, nil
+}
+
+// Locates the cluster-autoscaler deployments by name or by the commonly used labels
+func findClusterAutoscalers(client kubernetes.Interface) ([]appsv1.Deployment, error) {
+	list, err := client.AppsV1().Deployments(metav1.NamespaceAll).List(metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	deployments := []appsv1.Deployment{}
+	for _, deployment := range list.Items {
+		if isClusterAutoscaler(&deployment) {
+			deployments = append(deployments, deployment)
+		}
+	}
+
+	return deployments, nil
+}
+
+func isClusterAutoscaler(deployment *appsv1.Deployment) bool {
+	if deployment.Name == ClusterAutoscalerName {
+		return true
+	}
+
+	for key, values := range clusterAutoscalerLabels {
+		label, ok := deployment.Labels[key]
+		if !ok {
+			continue
+		}
+
+		for _, value := range values {
+			if label == value {
+				return true
+			}
+		}
+	}
+
+	return false
+}
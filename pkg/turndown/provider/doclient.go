@@ -0,0 +1,192 @@
+package provider
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"time"
+)
+
+const (
+	DOAPIEndpoint = "https://api.digitalocean.com"
+)
+
+// DOKSNodePool is the node pool representation returned by the DigitalOcean Kubernetes API
+type DOKSNodePool struct {
+	ID        string            `json:"id,omitempty"`
+	Name      string            `json:"name"`
+	Size      string            `json:"size,omitempty"`
+	Count     int32             `json:"count"`
+	Tags      []string          `json:"tags,omitempty"`
+	Labels    map[string]string `json:"labels,omitempty"`
+	AutoScale bool              `json:"auto_scale"`
+	MinNodes  int32             `json:"min_nodes"`
+	MaxNodes  int32             `json:"max_nodes"`
+	Nodes     []*DOKSNode       `json:"nodes,omitempty"`
+}
+
+// DOKSNode is a single node in a DOKSNodePool
+type DOKSNode struct {
+	ID        string `json:"id"`
+	Name      string `json:"name"`
+	DropletID string `json:"droplet_id"`
+}
+
+// DODroplet contains the fields of a droplet used to locate the owning cluster
+type DODroplet struct {
+	ID   int      `json:"id"`
+	Name string   `json:"name"`
+	Tags []string `json:"tags"`
+}
+
+// DOAPIError is returned when the DigitalOcean API responds with a non-2xx status code
+type DOAPIError struct {
+	StatusCode int    `json:"-"`
+	ID         string `json:"id"`
+	Message    string `json:"message"`
+}
+
+func (e *DOAPIError) Error() string {
+	return fmt.Sprintf("DigitalOcean API Error [%d] %s: %s", e.StatusCode, e.ID, e.Message)
+}
+
+// IsDOUnprocessable returns true if the error is a DigitalOcean API error rejecting the request as
+// invalid for the current resource, ie: resizing a node pool below its allowed minimum.
+func IsDOUnprocessable(err error) bool {
+	apiErr, ok := err.(*DOAPIError)
+	return ok && apiErr.StatusCode == http.StatusUnprocessableEntity
+}
+
+// DOClient is a minimal client for the DigitalOcean API operations required by turndown
+type DOClient struct {
+	endpoint string
+	token    string
+	client   *http.Client
+}
+
+// NewDOClient creates a new DOClient for the API endpoint using the provided access token.
+func NewDOClient(endpoint string, token string) *DOClient {
+	return &DOClient{
+		endpoint: strings.TrimSuffix(endpoint, "/"),
+		token:    token,
+		client: &http.Client{
+			Timeout: 30 * time.Second,
+			Transport: UserAgentTransport{
+				userAgent: KubecostTurndownUserAgent,
+				base:      http.DefaultTransport,
+			},
+		},
+	}
+}
+
+func (c *DOClient) GetDroplet(dropletID string) (*DODroplet, error) {
+	var resp struct {
+		Droplet *DODroplet `json:"droplet"`
+	}
+
+	err := c.do(http.MethodGet, fmt.Sprintf("/v2/droplets/%s", dropletID), nil, &resp)
+	if err != nil {
+		return nil, err
+	}
+
+	return resp.Droplet, nil
+}
+
+func (c *DOClient) ListNodePools(clusterID string) ([]*DOKSNodePool, error) {
+	var resp struct {
+		NodePools []*DOKSNodePool `json:"node_pools"`
+	}
+
+	err := c.do(http.MethodGet, fmt.Sprintf("/v2/kubernetes/clusters/%s/node_pools", clusterID), nil, &resp)
+	if err != nil {
+		return nil, err
+	}
+
+	return resp.NodePools, nil
+}
+
+func (c *DOClient) CreateNodePool(clusterID string, nodePool *DOKSNodePool) (*DOKSNodePool, error) {
+	var resp struct {
+		NodePool *DOKSNodePool `json:"node_pool"`
+	}
+
+	err := c.do(http.MethodPost, fmt.Sprintf("/v2/kubernetes/clusters/%s/node_pools", clusterID), nodePool, &resp)
+	if err != nil {
+		return nil, err
+	}
+
+	return resp.NodePool, nil
+}
+
+// UpdateNodePool replaces the name, count, tags, labels and auto scale settings of the node pool.
+func (c *DOClient) UpdateNodePool(clusterID string, nodePool *DOKSNodePool) (*DOKSNodePool, error) {
+	var resp struct {
+		NodePool *DOKSNodePool `json:"node_pool"`
+	}
+
+	update := &DOKSNodePool{
+		Name:      nodePool.Name,
+		Count:     nodePool.Count,
+		Tags:      nodePool.Tags,
+		Labels:    nodePool.Labels,
+		AutoScale: nodePool.AutoScale,
+		MinNodes:  nodePool.MinNodes,
+		MaxNodes:  nodePool.MaxNodes,
+	}
+
+	path := fmt.Sprintf("/v2/kubernetes/clusters/%s/node_pools/%s", clusterID, nodePool.ID)
+	err := c.do(http.MethodPut, path, update, &resp)
+	if err != nil {
+		return nil, err
+	}
+
+	return resp.NodePool, nil
+}
+
+// Executes the request against the API, decoding the json response into out
+func (c *DOClient) do(method string, path string, in interface{}, out interface{}) error {
+	var body *bytes.Reader
+	if in != nil {
+		data, err := json.Marshal(in)
+		if err != nil {
+			return err
+		}
+		body = bytes.NewReader(data)
+	} else {
+		body = bytes.NewReader(nil)
+	}
+
+	req, err := http.NewRequest(method, c.endpoint+path, body)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Bearer "+c.token)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	data, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		apiErr := &DOAPIError{StatusCode: resp.StatusCode}
+		json.Unmarshal(data, apiErr)
+		return apiErr
+	}
+
+	if out == nil || len(data) == 0 {
+		return nil
+	}
+
+	return json.Unmarshal(data, out)
+}
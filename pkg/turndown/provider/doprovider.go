@@ -0,0 +1,411 @@
+package provider
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/kubecost/cluster-turndown/pkg/file"
+	"github.com/kubecost/cluster-turndown/pkg/logging"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/klog"
+)
+
+const (
+	DOAccessKey           = "/var/keys/service-key.json"
+	DOAPIEndpointEnvVar   = "DIGITALOCEAN_API_URL"
+	DOClusterIDEnvVar     = "DIGITALOCEAN_CLUSTER_ID"
+	DOProviderIDPrefix    = "digitalocean://"
+	DOKSNodePoolLabel     = "doks.digitalocean.com/node-pool"
+	DOKSClusterTagPrefix  = "k8s:"
+	DOKSWorkerTag         = "k8s:worker"
+	DOTurndownPoolName    = "cluster-turndown"
+	DOTurndownPoolSize    = "s-1vcpu-2gb"
+	DOKSTagKeyValueSep    = ":"
+	DONodePoolPreviousKey = "cluster-turndown-previous"
+)
+
+// DigitalOcean NodePool based on a DOKS node pool
+type DONodePool struct {
+	pool      *DOKSNodePool
+	clusterID string
+	tags      map[string]string
+}
+
+func (np *DONodePool) Project() string         { return "" }
+func (np *DONodePool) Name() string            { return np.pool.Name }
+func (np *DONodePool) Zone() string            { return "" }
+func (np *DONodePool) ClusterID() string       { return np.clusterID }
+func (np *DONodePool) NodeCount() int32        { return np.pool.Count }
+func (np *DONodePool) AutoScaling() bool       { return np.pool.AutoScale }
+func (np *DONodePool) Tags() map[string]string { return np.tags }
+
+func (np *DONodePool) MinNodes() int32 {
+	if np.pool.AutoScale {
+		return np.pool.MinNodes
+	}
+	return np.pool.Count
+}
+
+func (np *DONodePool) MaxNodes() int32 {
+	if np.pool.AutoScale {
+		return np.pool.MaxNodes
+	}
+	return np.pool.Count
+}
+
+type DOAccessToken struct {
+	AccessToken string `json:"access_token"`
+}
+
+// ComputeProvider for DigitalOcean Kubernetes
+type DOProvider struct {
+	kubernetes kubernetes.Interface
+	client     *DOClient
+	clusterID  string
+	log        logging.NamedLogger
+}
+
+func NewDOProvider(kubernetes kubernetes.Interface) ComputeProvider {
+	endpoint := os.Getenv(DOAPIEndpointEnvVar)
+	if endpoint == "" {
+		endpoint = DOAPIEndpoint
+	}
+
+	token, err := loadDOAccessToken()
+	if err != nil {
+		klog.V(1).Infof("Failed to load service account.")
+	}
+
+	client := NewDOClient(endpoint, token)
+
+	return &DOProvider{
+		kubernetes: kubernetes,
+		client:     client,
+		clusterID:  findDOClusterID(kubernetes, client),
+		log:        logging.NamedLogger("DOProvider"),
+	}
+}
+
+func (p *DOProvider) IsServiceAccountKey() bool {
+	return file.FileExists(DOAccessKey)
+}
+
+func (p *DOProvider) IsTurndownNodePool() bool {
+	pools, err := p.client.ListNodePools(p.clusterID)
+	if err != nil {
+		return false
+	}
+
+	for _, pool := range pools {
+		if pool.Name == DOTurndownPoolName {
+			return pool.Count == 1
+		}
+	}
+
+	return false
+}
+
+func (p *DOProvider) CreateSingletonNodePool() error {
+	pool, err := p.client.CreateNodePool(p.clusterID, &DOKSNodePool{
+		Name:  DOTurndownPoolName,
+		Size:  DOTurndownPoolSize,
+		Count: 1,
+		Labels: map[string]string{
+			TurndownNodeLabel: "true",
+		},
+	})
+	if err != nil {
+		return err
+	}
+	p.log.Log("Create Singleton Node: %s", pool.ID)
+
+	return WaitUntilNodeCreated(p.kubernetes, TurndownNodeLabel, "true", DOTurndownPoolName, 5*time.Second, 5*time.Minute)
+}
+
+func (p *DOProvider) GetPoolID(node *v1.Node) string {
+	if pool, ok := node.Labels[DOKSNodePoolLabel]; ok {
+		return pool
+	}
+
+	// Fallback to locating the droplet in the node pools
+	dropletID := strings.TrimPrefix(node.Spec.ProviderID, DOProviderIDPrefix)
+	pools, err := p.client.ListNodePools(p.clusterID)
+	if err != nil {
+		return ""
+	}
+
+	for _, pool := range pools {
+		for _, n := range pool.Nodes {
+			if n.DropletID == dropletID {
+				return pool.Name
+			}
+		}
+	}
+
+	return ""
+}
+
+func (p *DOProvider) GetNodePools() ([]NodePool, error) {
+	if p.clusterID == "" {
+		return nil, fmt.Errorf("Failed to locate the DigitalOcean cluster id. Set the %s environment variable.", DOClusterIDEnvVar)
+	}
+
+	res, err := p.client.ListNodePools(p.clusterID)
+	if err != nil {
+		return nil, err
+	}
+
+	pools := []NodePool{}
+	for _, pool := range res {
+		pools = append(pools, &DONodePool{
+			pool:      pool,
+			clusterID: p.clusterID,
+			tags:      doTagsToMap(pool.Tags),
+		})
+	}
+
+	return pools, nil
+}
+
+func (p *DOProvider) SetNodePoolSizes(nodePools []NodePool, size int32) NodePoolResults {
+	results := NodePoolResults{}
+
+	for _, np := range nodePools {
+		pool, ok := np.(*DONodePool)
+		if !ok {
+			results = append(results, NewNodePoolResult(np, size, np.NodeCount(), fmt.Errorf("NodePool: %s is not a DigitalOcean NodePool", np.Name())))
+			continue
+		}
+
+		// Keep the range stored by a previous attempt, the pool may already be resized
+		current := np.NodeCount()
+		previous, ok := np.Tags()[DONodePoolPreviousKey]
+		if !ok {
+			previous = doFlatRange(np.MinNodes(), np.MaxNodes(), current, np.AutoScaling())
+		}
+
+		update := *pool.pool
+		update.Count = size
+		update.AutoScale = false
+		update.Tags = withDOTag(pool.pool.Tags, DONodePoolPreviousKey, previous)
+
+		err := p.updateNodePool(pool, &update)
+
+		// DigitalOcean rejects resizing pools below their allowed minimum, leave those pools as they are
+		if IsDOUnprocessable(err) {
+			p.log.Warn("Node Pool: %s cannot be resized to %d: %s", np.Name(), size, err.Error())
+			results = append(results, &NodePoolResult{
+				NodePool:      np,
+				RequestedSize: size,
+				PreviousSize:  current,
+				Outcome:       NodePoolSkipped,
+				Error:         err,
+			})
+			continue
+		}
+
+		results = append(results, NewNodePoolResult(np, size, current, err))
+	}
+
+	return results
+}
+
+func (p *DOProvider) ResetNodePoolSizes(nodePools []NodePool) NodePoolResults {
+	results := NodePoolResults{}
+
+	for _, np := range nodePools {
+		pool, ok := np.(*DONodePool)
+		if !ok {
+			results = append(results, NewNodePoolResult(np, 0, np.NodeCount(), fmt.Errorf("NodePool: %s is not a DigitalOcean NodePool", np.Name())))
+			continue
+		}
+
+		rangeTag, ok := np.Tags()[DONodePoolPreviousKey]
+		if !ok {
+			p.log.Err("Failed to locate tag: %s for NodePool: %s", DONodePoolPreviousKey, np.Name())
+			results = append(results, &NodePoolResult{
+				NodePool:     np,
+				PreviousSize: np.NodeCount(),
+				Outcome:      NodePoolSkipped,
+				Error:        fmt.Errorf("Missing tag: %s", DONodePoolPreviousKey),
+			})
+			continue
+		}
+
+		min, max, count, autoScale, err := doExpandRange(rangeTag)
+		if err != nil {
+			p.log.Err("Failed to parse range used to resize node pool: %s", err.Error())
+			results = append(results, &NodePoolResult{
+				NodePool:     np,
+				PreviousSize: np.NodeCount(),
+				Outcome:      NodePoolSkipped,
+				Error:        err,
+			})
+			continue
+		}
+
+		current := np.NodeCount()
+
+		update := *pool.pool
+		update.Count = count
+		update.AutoScale = autoScale
+		update.Tags = withoutDOTag(pool.pool.Tags, DONodePoolPreviousKey)
+		if autoScale {
+			update.MinNodes = min
+			update.MaxNodes = max
+		}
+
+		err = p.updateNodePool(pool, &update)
+		results = append(results, NewNodePoolResult(np, count, current, err))
+	}
+
+	return results
+}
+
+// Updates the DOKS node pool, and replaces the pool and tags on the NodePool with the result
+func (p *DOProvider) updateNodePool(np *DONodePool, update *DOKSNodePool) error {
+	p.log.Log("Resizing Node Pool: %s to %d", np.Name(), update.Count)
+
+	pool, err := p.client.UpdateNodePool(p.clusterID, update)
+	if err != nil {
+		p.log.Err("Updating Node Pool: %s", err.Error())
+		return err
+	}
+
+	// Keep the requested tags in case the response omits them
+	if pool.Tags == nil {
+		pool.Tags = update.Tags
+	}
+
+	np.pool = pool
+	np.tags = doTagsToMap(pool.Tags)
+	return nil
+}
+
+func loadDOAccessToken() (string, error) {
+	if !file.FileExists(DOAccessKey) {
+		return "", fmt.Errorf("Failed to locate service account file: %s", DOAccessKey)
+	}
+
+	result, err := ioutil.ReadFile(DOAccessKey)
+	if err != nil {
+		return "", err
+	}
+
+	var at DOAccessToken
+	err = json.Unmarshal(result, &at)
+	if err != nil {
+		return "", err
+	}
+
+	if at.AccessToken == "" {
+		return "", errors.New("Service account file does not contain an access_token")
+	}
+
+	return at.AccessToken, nil
+}
+
+// Locates the DOKS cluster id from the environment, or from the k8s:<cluster-id> tag on the droplet backing
+// one of the nodes.
+func findDOClusterID(c kubernetes.Interface, client *DOClient) string {
+	if clusterID := os.Getenv(DOClusterIDEnvVar); clusterID != "" {
+		return clusterID
+	}
+
+	log := logging.NamedLogger("DOProvider")
+	nodes, err := c.CoreV1().Nodes().List(metav1.ListOptions{})
+	if err != nil {
+		log.Err("Failed to locate DigitalOcean Cluster: %s", err.Error())
+		return ""
+	}
+
+	if len(nodes.Items) == 0 {
+		log.Err("Failed to locate any kubernetes nodes.")
+		return ""
+	}
+
+	dropletID := strings.TrimPrefix(nodes.Items[0].Spec.ProviderID, DOProviderIDPrefix)
+	droplet, err := client.GetDroplet(dropletID)
+	if err != nil {
+		log.Err("Failed to locate Droplet: %s - %s", dropletID, err.Error())
+		return ""
+	}
+
+	for _, tag := range droplet.Tags {
+		if strings.HasPrefix(tag, DOKSClusterTagPrefix) && tag != DOKSWorkerTag {
+			return strings.TrimPrefix(tag, DOKSClusterTagPrefix)
+		}
+	}
+
+	log.Err("Failed to locate cluster tag on Droplet: %s", dropletID)
+	return ""
+}
+
+// DigitalOcean tags are flat strings, so key:value tags are split into the map
+func doTagsToMap(tags []string) map[string]string {
+	m := make(map[string]string)
+	for _, tag := range tags {
+		kv := strings.SplitN(tag, DOKSTagKeyValueSep, 2)
+		if len(kv) == 2 {
+			m[kv[0]] = kv[1]
+		} else {
+			m[tag] = ""
+		}
+	}
+
+	return m
+}
+
+func withDOTag(tags []string, key string, value string) []string {
+	return append(withoutDOTag(tags, key), key+DOKSTagKeyValueSep+value)
+}
+
+func withoutDOTag(tags []string, key string) []string {
+	result := []string{}
+	for _, tag := range tags {
+		if tag == key || strings.HasPrefix(tag, key+DOKSTagKeyValueSep) {
+			continue
+		}
+		result = append(result, tag)
+	}
+
+	return result
+}
+
+// DigitalOcean tags only allow letters, numbers, colons, dashes and underscores, so the previous range
+// is stored as min_max_count_autoscale
+func doFlatRange(min, max, count int32, autoScale bool) string {
+	return fmt.Sprintf("%d_%d_%d_%t", min, max, count, autoScale)
+}
+
+func doExpandRange(s string) (int32, int32, int32, bool, error) {
+	values := strings.Split(s, "_")
+	if len(values) != 4 {
+		return 0, 0, 0, false, fmt.Errorf("Failed to parse range: %s", s)
+	}
+
+	var parsed [3]int32
+	for i := 0; i < 3; i++ {
+		v, err := strconv.ParseInt(values[i], 10, 32)
+		if err != nil {
+			return 0, 0, 0, false, err
+		}
+		parsed[i] = int32(v)
+	}
+
+	autoScale, err := strconv.ParseBool(values[3])
+	if err != nil {
+		return 0, 0, 0, false, err
+	}
+
+	return parsed[0], parsed[1], parsed[2], autoScale, nil
+}
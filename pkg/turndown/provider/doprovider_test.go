@@ -0,0 +1,214 @@
+package provider
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/kubecost/cluster-turndown/pkg/logging"
+)
+
+// Stub of the DigitalOcean Kubernetes node pool API holding the node pools of a single cluster
+type testDOAPI struct {
+	lock      sync.Mutex
+	clusterID string
+	pools     map[string]*DOKSNodePool
+	minimums  map[string]int32
+}
+
+func (api *testDOAPI) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	api.lock.Lock()
+	defer api.lock.Unlock()
+
+	if r.Header.Get("Authorization") != "Bearer test-token" {
+		w.WriteHeader(http.StatusUnauthorized)
+		json.NewEncoder(w).Encode(&DOAPIError{ID: "unauthorized", Message: "Unable to authenticate you"})
+		return
+	}
+
+	prefix := "/v2/kubernetes/clusters/" + api.clusterID + "/node_pools"
+	if !strings.HasPrefix(r.URL.Path, prefix) {
+		w.WriteHeader(http.StatusNotFound)
+		json.NewEncoder(w).Encode(&DOAPIError{ID: "not_found", Message: "The resource you were accessing could not be found."})
+		return
+	}
+
+	poolID := strings.TrimPrefix(strings.TrimPrefix(r.URL.Path, prefix), "/")
+	switch {
+	case r.Method == http.MethodGet && poolID == "":
+		pools := []*DOKSNodePool{}
+		for _, pool := range api.pools {
+			pools = append(pools, pool)
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{"node_pools": pools})
+
+	case r.Method == http.MethodPut && api.pools[poolID] != nil:
+		var update DOKSNodePool
+		json.NewDecoder(r.Body).Decode(&update)
+
+		if update.Count < api.minimums[poolID] {
+			w.WriteHeader(http.StatusUnprocessableEntity)
+			json.NewEncoder(w).Encode(&DOAPIError{ID: "unprocessable_entity", Message: "count is below the allowed minimum"})
+			return
+		}
+
+		update.ID = poolID
+		update.Size = api.pools[poolID].Size
+		api.pools[poolID] = &update
+		json.NewEncoder(w).Encode(map[string]interface{}{"node_pool": &update})
+
+	default:
+		w.WriteHeader(http.StatusNotFound)
+		json.NewEncoder(w).Encode(&DOAPIError{ID: "not_found", Message: "The resource you were accessing could not be found."})
+	}
+}
+
+func (api *testDOAPI) pool(id string) DOKSNodePool {
+	api.lock.Lock()
+	defer api.lock.Unlock()
+
+	return *api.pools[id]
+}
+
+// Creates a DOProvider backed by a stub API with a fixed size "workers" pool and an autoscaling
+// "autoscale" pool. The returned server must be closed.
+func newTestDOProvider() (*DOProvider, *testDOAPI, *httptest.Server) {
+	api := &testDOAPI{
+		clusterID: "cluster-1",
+		pools: map[string]*DOKSNodePool{
+			"pool-1": {ID: "pool-1", Name: "workers", Size: "s-2vcpu-4gb", Count: 3, Tags: []string{"k8s", "team:web"}},
+			"pool-2": {ID: "pool-2", Name: "autoscale", Size: "s-2vcpu-4gb", Count: 2, AutoScale: true, MinNodes: 1, MaxNodes: 5},
+		},
+	}
+
+	server := httptest.NewServer(api)
+
+	return &DOProvider{
+		kubernetes: newTestKubernetes(),
+		client:     NewDOClient(server.URL, "test-token"),
+		clusterID:  api.clusterID,
+		log:        logging.NamedLogger("DOProvider"),
+	}, api, server
+}
+
+func TestDOProviderListsNodePools(t *testing.T) {
+	p, _, server := newTestDOProvider()
+	defer server.Close()
+
+	pools := testNodePoolsByName(t, p)
+	if len(pools) != 2 {
+		t.Fatalf("Expected 2 node pools, got %d", len(pools))
+	}
+
+	workers := pools["workers"]
+	if workers.NodeCount() != 3 || workers.AutoScaling() || workers.ClusterID() != "cluster-1" {
+		t.Errorf("Expected 3 fixed nodes in cluster-1, got %d (autoscaling: %t) in %s", workers.NodeCount(), workers.AutoScaling(), workers.ClusterID())
+	}
+	if workers.Tags()["team"] != "web" {
+		t.Errorf("Expected tag team:web, got %v", workers.Tags())
+	}
+
+	autoscale := pools["autoscale"]
+	if !autoscale.AutoScaling() || autoscale.MinNodes() != 1 || autoscale.MaxNodes() != 5 {
+		t.Errorf("Expected autoscaling 1-5 nodes, got %d-%d (autoscaling: %t)", autoscale.MinNodes(), autoscale.MaxNodes(), autoscale.AutoScaling())
+	}
+}
+
+func TestDOProviderReturnsAPIErrors(t *testing.T) {
+	p, _, server := newTestDOProvider()
+	defer server.Close()
+	p.client.token = "invalid"
+
+	_, err := p.GetNodePools()
+	apiErr, ok := err.(*DOAPIError)
+	if !ok || apiErr.StatusCode != http.StatusUnauthorized || apiErr.ID != "unauthorized" {
+		t.Fatalf("Expected an unauthorized DOAPIError, got %v", err)
+	}
+}
+
+func TestDOProviderScaleToZeroAndRestore(t *testing.T) {
+	p, api, server := newTestDOProvider()
+	defer server.Close()
+
+	pools := testNodePoolsByName(t, p)
+	results := p.SetNodePoolSizes([]NodePool{pools["workers"], pools["autoscale"]}, 0)
+	if err := results.Err(); err != nil {
+		t.Fatalf("Failed to scale down: %s", err.Error())
+	}
+
+	workers := api.pool("pool-1")
+	if workers.Count != 0 || workers.AutoScale {
+		t.Errorf("Expected workers at 0 fixed nodes, got %d (autoscaling: %t)", workers.Count, workers.AutoScale)
+	}
+	if tags := doTagsToMap(workers.Tags); tags[DONodePoolPreviousKey] != "3_3_3_false" || tags["team"] != "web" {
+		t.Errorf("Expected previous range 3_3_3_false and existing tags, got %v", workers.Tags)
+	}
+
+	// Auto scaling is disabled so the pool can be resized to 0
+	autoscale := api.pool("pool-2")
+	if autoscale.Count != 0 || autoscale.AutoScale {
+		t.Errorf("Expected autoscale at 0 fixed nodes, got %d (autoscaling: %t)", autoscale.Count, autoscale.AutoScale)
+	}
+	if tags := doTagsToMap(autoscale.Tags); tags[DONodePoolPreviousKey] != "1_5_2_true" {
+		t.Errorf("Expected previous range 1_5_2_true, got %v", autoscale.Tags)
+	}
+
+	// Scaling down again must keep the original range rather than recording 0
+	pools = testNodePoolsByName(t, p)
+	results = p.SetNodePoolSizes([]NodePool{pools["workers"], pools["autoscale"]}, 0)
+	if err := results.Err(); err != nil {
+		t.Fatalf("Failed to scale down again: %s", err.Error())
+	}
+	if tags := doTagsToMap(api.pool("pool-2").Tags); tags[DONodePoolPreviousKey] != "1_5_2_true" {
+		t.Errorf("Expected previous range 1_5_2_true after scaling down again, got %v", api.pool("pool-2").Tags)
+	}
+
+	pools = testNodePoolsByName(t, p)
+	results = p.ResetNodePoolSizes([]NodePool{pools["workers"], pools["autoscale"]})
+	if err := results.Err(); err != nil {
+		t.Fatalf("Failed to restore: %s", err.Error())
+	}
+
+	workers = api.pool("pool-1")
+	if workers.Count != 3 || workers.AutoScale {
+		t.Errorf("Expected workers at 3 fixed nodes, got %d (autoscaling: %t)", workers.Count, workers.AutoScale)
+	}
+	if _, ok := doTagsToMap(workers.Tags)[DONodePoolPreviousKey]; ok {
+		t.Errorf("Expected previous range tag to be removed, got %v", workers.Tags)
+	}
+
+	autoscale = api.pool("pool-2")
+	if autoscale.Count != 2 || !autoscale.AutoScale || autoscale.MinNodes != 1 || autoscale.MaxNodes != 5 {
+		t.Errorf("Expected autoscale at 2 nodes autoscaling 1-5, got %d (autoscaling: %t) %d-%d",
+			autoscale.Count, autoscale.AutoScale, autoscale.MinNodes, autoscale.MaxNodes)
+	}
+}
+
+func TestDOProviderSkipsUnprocessableNodePools(t *testing.T) {
+	p, api, server := newTestDOProvider()
+	defer server.Close()
+	api.minimums = map[string]int32{"pool-1": 1}
+
+	pools := testNodePoolsByName(t, p)
+	results := p.SetNodePoolSizes([]NodePool{pools["workers"], pools["autoscale"]}, 0)
+	if err := results.Err(); err != nil {
+		t.Fatalf("Expected skipped node pools not to fail the resize: %s", err.Error())
+	}
+
+	for _, result := range results {
+		expected := NodePoolResized
+		if result.NodePool.Name() == "workers" {
+			expected = NodePoolSkipped
+		}
+		if result.Outcome != expected {
+			t.Errorf("Expected %s for node pool: %s, got %s", expected, result.NodePool.Name(), result.Outcome)
+		}
+	}
+
+	if workers := api.pool("pool-1"); workers.Count != 3 {
+		t.Errorf("Expected workers to keep 3 nodes, got %d", workers.Count)
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/kubecost/cluster-turndown/pkg/async"
@@ -11,9 +12,12 @@ import (
 	"github.com/kubecost/cluster-turndown/pkg/logging"
 
 	gax "github.com/googleapis/gax-go/v2"
+	compute "google.golang.org/api/compute/v1"
 	container "google.golang.org/genproto/googleapis/container/v1"
 
 	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
 	"k8s.io/client-go/kubernetes"
 
 	gke "cloud.google.com/go/container/apiv1"
@@ -26,6 +30,14 @@ const (
 	GKECredsEnvVar        = "GOOGLE_APPLICATION_CREDENTIALS"
 	GKEAuthServiceAccount = "/var/keys/service-key.json"
 	GKETurndownPoolName   = "cluster-turndown"
+
+	// GKE resource labels do not allow '.' or '/', so the previous range for each node pool is
+	// stored in a cluster resource label prefixed with this key, formatted as min_max_count
+	GKENodePoolPreviousKeyPrefix = "turndown-previous-"
+
+	// Time allowed for a single attempt to resize the node pools. Node pools which fail to resize are
+	// retried by the turndown manager.
+	GKEResizeTimeout = 5 * time.Minute
 )
 
 var (
@@ -46,6 +58,9 @@ type GKENodePool struct {
 	count       int32
 	autoscaling bool
 	tags        map[string]string
+
+	// The managed instance group backing the node pool in each zone
+	instanceGroups []string
 }
 
 func (np *GKENodePool) Name() string            { return np.name }
@@ -62,6 +77,7 @@ func (np *GKENodePool) Tags() map[string]string { return np.tags }
 type GKEProvider struct {
 	kubernetes     kubernetes.Interface
 	clusterManager *gke.ClusterManagerClient
+	compute        *compute.Service
 	metadata       *GKEMetaData
 	log            logging.NamedLogger
 }
@@ -72,9 +88,15 @@ func NewGKEProvider(kubernetes kubernetes.Interface) ComputeProvider {
 		klog.V(1).Infof("Failed to load service account.")
 	}
 
+	computeService, err := newGKEComputeService()
+	if err != nil {
+		klog.V(1).Infof("Failed to load compute service: %s", err.Error())
+	}
+
 	return &GKEProvider{
 		kubernetes:     kubernetes,
 		clusterManager: clusterManager,
+		compute:        computeService,
 		metadata:       NewGKEMetaData(),
 		log:            logging.NamedLogger("GKEProvider"),
 	}
@@ -178,10 +200,25 @@ func (p *GKEProvider) GetNodePools() ([]NodePool, error) {
 		return nil, err
 	}
 
+	// The initial node count does not reflect resizes after creation, so use the live node counts
+	liveCounts, err := p.liveNodeCounts()
+	if err != nil {
+		p.log.Warn("Failed to load live node counts, using initial node counts: %s", err.Error())
+	}
+
+	// Previous sizes stored by turndown on the cluster resource labels
+	clusterLabels, err := p.clusterLabels()
+	if err != nil {
+		p.log.Warn("Failed to load cluster labels: %s", err.Error())
+	}
+
 	pools := []NodePool{}
 
 	for _, np := range resp.GetNodePools() {
 		nodeCount := np.GetInitialNodeCount()
+		if liveCounts != nil {
+			nodeCount = gkeZoneNodeCount(liveCounts[np.GetName()], len(np.GetInstanceGroupUrls()))
+		}
 		autoscaling := np.Autoscaling.GetEnabled()
 
 		var min int32 = nodeCount
@@ -191,9 +228,12 @@ func (p *GKEProvider) GetNodePools() ([]NodePool, error) {
 			max = np.Autoscaling.GetMaxNodeCount()
 		}
 
-		tags := np.GetConfig().GetLabels()
-		if tags == nil {
-			tags = make(map[string]string)
+		tags := make(map[string]string)
+		for k, v := range np.GetConfig().GetLabels() {
+			tags[k] = v
+		}
+		if previous, ok := clusterLabels[gkePreviousKeyFor(np.GetName())]; ok {
+			tags[GKENodePoolPreviousKeyPrefix] = previous
 		}
 
 		pools = append(pools, &GKENodePool{
@@ -206,107 +246,258 @@ func (p *GKEProvider) GetNodePools() ([]NodePool, error) {
 			count:       nodeCount,
 			autoscaling: autoscaling,
 			tags:        tags,
+
+			instanceGroups: np.GetInstanceGroupUrls(),
 		})
 	}
 
 	return pools, nil
 }
 
-func (p *GKEProvider) SetNodePoolSizes(nodePools []NodePool, size int32) error {
+func (p *GKEProvider) SetNodePoolSizes(nodePools []NodePool, size int32) NodePoolResults {
 	if len(nodePools) == 0 {
-		return nil
+		return NodePoolResults{}
 	}
 
-	requests := []*container.SetNodePoolSizeRequest{}
-	for _, nodePool := range nodePools {
-		requests = append(requests, &container.SetNodePoolSizeRequest{
-			ProjectId:  nodePool.Project(),
-			ClusterId:  nodePool.ClusterID(),
-			Zone:       nodePool.Zone(),
-			NodePoolId: nodePool.Name(),
-			NodeCount:  size,
-		})
+	err := p.storePreviousRanges(nodePools)
+	if err != nil {
+		results := NodePoolResults{}
+		for _, np := range nodePools {
+			results = append(results, NewNodePoolResult(np, size, np.NodeCount(), err))
+		}
+		return results
+	}
 
-		p.log.Log("Resizing NodePool to 0 [Proj: %s, ClusterId: %s, Zone: %s, PoolID: %s]",
-			nodePool.Project(),
-			nodePool.ClusterID(),
-			nodePool.Zone(),
-			nodePool.Name())
+	return p.resizeNodePools(nodePools, func(NodePool) int32 { return size })
+}
+
+// RemoveNodes deletes the instances backing the nodes from their managed instance groups, which reduces
+// the target size of each group, so the groups do not choose other instances to delete.
+func (p *GKEProvider) RemoveNodes(np NodePool, nodes []v1.Node) *NodePoolResult {
+	pool, ok := np.(*GKENodePool)
+	if !ok {
+		return NewNodePoolResult(np, np.NodeCount(), np.NodeCount(), fmt.Errorf("NodePool: %s is not a GKE NodePool", np.Name()))
 	}
 
-	ctx, cancel := context.WithCancel(context.TODO())
+	// The compute service failed to load, ie: missing credentials
+	if p.compute == nil {
+		return NewNodePoolResult(np, np.NodeCount(), np.NodeCount(), fmt.Errorf("Failed to remove nodes from NodePool: %s, the compute service is not available", np.Name()))
+	}
 
-	waitChannel := async.NewWaitChannel()
-	waitChannel.Add(len(requests))
+	// The node count is per zone, so the requested size is the remaining nodes divided across the zones
+	size := np.NodeCount()
+	if liveCounts, err := p.liveNodeCounts(); err == nil {
+		remaining := liveCounts[np.Name()] - int32(len(nodes))
+		if remaining < 0 {
+			remaining = 0
+		}
+		size = gkeZoneNodeCount(remaining, len(pool.instanceGroups))
+	}
 
-	for _, req := range requests {
-		go func(request *container.SetNodePoolSizeRequest) {
-			defer waitChannel.Done()
+	err := p.storePreviousRanges([]NodePool{np})
+	if err != nil {
+		return NewNodePoolResult(np, size, np.NodeCount(), err)
+	}
 
-			for {
-				_, err := p.clusterManager.SetNodePoolSize(ctx, request, options...)
-				if err == nil {
-					p.log.Log("Resized NodePool Successfully: %s", request.NodePoolId)
-					return
-				}
+	// Group the instances by the managed instance group in their zone
+	instances := make(map[string][]string)
+	for _, node := range nodes {
+		project, zone, _ := p.projectInfoFor(&node)
+		group, ok := pool.instanceGroupFor(project, zone)
+		if !ok {
+			return NewNodePoolResult(np, size, np.NodeCount(), fmt.Errorf("Failed to locate instance group for node: %s in zone: %s", node.Name, zone))
+		}
 
-				p.log.Log("NodePool operation already in queue, retrying...")
+		instances[group] = append(instances[group], fmt.Sprintf("zones/%s/instances/%s", zone, gkeInstanceName(&node)))
+	}
 
-				select {
-				case <-time.After(30 * time.Second):
-				case <-ctx.Done():
-					return
-				}
+	for group, groupInstances := range instances {
+		project, zone, name := gkeInstanceGroupInfo(group)
+
+		p.log.Log("Deleting %d instance(s) from Instance Group: %s [%s]", len(groupInstances), name, strings.Join(groupInstances, ", "))
+		op, err := p.compute.InstanceGroupManagers.DeleteInstances(project, zone, name, &compute.InstanceGroupManagersDeleteInstancesRequest{
+			Instances: groupInstances,
+		}).Do()
+		if err != nil {
+			p.log.Err("Deleting Instances: %s", err.Error())
+			return NewNodePoolResult(np, size, np.NodeCount(), err)
+		}
+
+		err = p.waitForZoneOperation(project, zone, op.Name)
+		if err != nil {
+			p.log.Err("Deleting Instances: %s", err.Error())
+			return NewNodePoolResult(np, size, np.NodeCount(), err)
+		}
+	}
+
+	return NewNodePoolResult(np, size, np.NodeCount(), nil)
+}
+
+// NodePoolZoneFor returns the zone of the node if the node pool has an instance group in more than one
+// zone, as the node count of those node pools is per zone.
+func (p *GKEProvider) NodePoolZoneFor(np NodePool, node *v1.Node) string {
+	pool, ok := np.(*GKENodePool)
+	if !ok || len(pool.instanceGroups) <= 1 {
+		return ""
+	}
+
+	_, zone, _ := p.projectInfoFor(node)
+	return zone
+}
+
+// Persists the current ranges of the node pools before resizing, so they can be restored after a pod
+// restart. Ranges stored by a previous attempt are kept, as the node pools may already be scaled down.
+func (p *GKEProvider) storePreviousRanges(nodePools []NodePool) error {
+	ranges := make(map[string]string)
+	for _, np := range nodePools {
+		ranges[gkePreviousKeyFor(np.Name())] = gkeFlatRange(np.MinNodes(), np.MaxNodes(), np.NodeCount())
+	}
+
+	err := p.updateClusterLabels(func(labels map[string]string) {
+		for k, v := range ranges {
+			if previous, ok := labels[k]; ok {
+				ranges[k] = previous
+				continue
 			}
-		}(req)
+			labels[k] = v
+		}
+	})
+	if err != nil {
+		p.log.Err("Failed to store previous node pool sizes: %s", err.Error())
+		return err
 	}
 
-	defer cancel()
+	for _, np := range nodePools {
+		np.Tags()[GKENodePoolPreviousKeyPrefix] = ranges[gkePreviousKeyFor(np.Name())]
+	}
 
-	select {
-	case <-waitChannel.Wait():
-		return nil
-	case <-time.After(30 * time.Minute):
-		return fmt.Errorf("Resize Requests timed out after 30 minutes.")
+	return nil
+}
+
+// Waits until the zonal compute operation is done, returning any errors reported by the operation
+func (p *GKEProvider) waitForZoneOperation(project, zone, operation string) error {
+	return wait.PollImmediate(5*time.Second, GKEResizeTimeout, func() (bool, error) {
+		op, err := p.compute.ZoneOperations.Get(project, zone, operation).Do()
+		if err != nil {
+			return false, err
+		}
+
+		if op.Status != "DONE" {
+			return false, nil
+		}
+
+		if op.Error != nil && len(op.Error.Errors) > 0 {
+			errs := []string{}
+			for _, e := range op.Error.Errors {
+				errs = append(errs, e.Message)
+			}
+			return false, fmt.Errorf("Operation: %s failed: [%s]", operation, strings.Join(errs, ", "))
+		}
+
+		return true, nil
+	})
+}
+
+func (p *GKEProvider) ResetNodePoolSizes(nodePools []NodePool) NodePoolResults {
+	results := NodePoolResults{}
+	counts := make(map[string]int32)
+	toReset := []NodePool{}
+
+	for _, np := range nodePools {
+		rangeTag, ok := np.Tags()[GKENodePoolPreviousKeyPrefix]
+		if !ok {
+			p.log.Err("Failed to locate label: %s for NodePool: %s", gkePreviousKeyFor(np.Name()), np.Name())
+			results = append(results, &NodePoolResult{
+				NodePool:     np,
+				PreviousSize: np.NodeCount(),
+				Outcome:      NodePoolSkipped,
+				Error:        fmt.Errorf("Missing label: %s", gkePreviousKeyFor(np.Name())),
+			})
+			continue
+		}
+
+		_, _, count := expandRange(strings.Replace(rangeTag, "_", "/", -1))
+		if count < 0 {
+			p.log.Err("Failed to parse range used to resize node pool.")
+			results = append(results, &NodePoolResult{
+				NodePool:     np,
+				PreviousSize: np.NodeCount(),
+				Outcome:      NodePoolSkipped,
+				Error:        fmt.Errorf("Failed to parse range: %s", rangeTag),
+			})
+			continue
+		}
+
+		counts[np.Name()] = int32(count)
+		toReset = append(toReset, np)
 	}
+
+	resetResults := p.resizeNodePools(toReset, func(np NodePool) int32 { return counts[np.Name()] })
+
+	// Remove the stored ranges for all node pools that were restored
+	resized := resetResults.Resized()
+	if len(resized) > 0 {
+		err := p.updateClusterLabels(func(labels map[string]string) {
+			for _, np := range resized {
+				delete(labels, gkePreviousKeyFor(np.Name()))
+			}
+		})
+		if err != nil {
+			p.log.Err("Failed to remove previous node pool size labels: %s", err.Error())
+		}
+
+		for _, np := range resized {
+			delete(np.Tags(), GKENodePoolPreviousKeyPrefix)
+		}
+	}
+
+	return append(results, resetResults...)
 }
 
-func (p *GKEProvider) ResetNodePoolSizes(nodePools []NodePool) error {
+// Concurrently resizes each of the node pools to the size returned by sizeFor, retrying while other
+// operations are queued. Any node pool which has not completed resizing within GKEResizeTimeout is
+// marked as failed.
+func (p *GKEProvider) resizeNodePools(nodePools []NodePool, sizeFor func(NodePool) int32) NodePoolResults {
 	if len(nodePools) == 0 {
-		return nil
+		return NodePoolResults{}
 	}
 
-	requests := []*container.SetNodePoolSizeRequest{}
-	for _, nodePool := range nodePools {
-		requests = append(requests, &container.SetNodePoolSizeRequest{
+	results := make(NodePoolResults, len(nodePools))
+	lock := new(sync.Mutex)
+
+	ctx, cancel := context.WithCancel(context.TODO())
+	defer cancel()
+
+	waitChannel := async.NewWaitChannel()
+	waitChannel.Add(len(nodePools))
+
+	for i, nodePool := range nodePools {
+		request := &container.SetNodePoolSizeRequest{
 			ProjectId:  nodePool.Project(),
 			ClusterId:  nodePool.ClusterID(),
 			Zone:       nodePool.Zone(),
 			NodePoolId: nodePool.Name(),
-			NodeCount:  nodePool.NodeCount(),
-		})
+			NodeCount:  sizeFor(nodePool),
+		}
 
-		p.log.Log("Resizing NodePool to 0 %d [Proj: %s, ClusterId: %s, Zone: %s, PoolId: %s]",
-			nodePool.NodeCount(),
+		p.log.Log("Resizing NodePool to %d [Proj: %s, ClusterId: %s, Zone: %s, PoolID: %s]",
+			request.NodeCount,
 			nodePool.Project(),
 			nodePool.ClusterID(),
 			nodePool.Zone(),
 			nodePool.Name())
-	}
 
-	ctx, cancel := context.WithCancel(context.TODO())
-
-	waitChannel := async.NewWaitChannel()
-	waitChannel.Add(len(requests))
-
-	for _, req := range requests {
-		go func(request *container.SetNodePoolSizeRequest) {
+		go func(index int, np NodePool, request *container.SetNodePoolSizeRequest) {
 			defer waitChannel.Done()
 
 			for {
 				_, err := p.clusterManager.SetNodePoolSize(ctx, request, options...)
 				if err == nil {
 					p.log.Log("Resized NodePool Successfully: %s", request.NodePoolId)
+
+					lock.Lock()
+					results[index] = NewNodePoolResult(np, request.NodeCount, np.NodeCount(), nil)
+					lock.Unlock()
 					return
 				}
 
@@ -318,17 +509,114 @@ func (p *GKEProvider) ResetNodePoolSizes(nodePools []NodePool) error {
 					return
 				}
 			}
-		}(req)
+		}(i, nodePool, request)
 	}
 
-	defer cancel()
-
 	select {
 	case <-waitChannel.Wait():
-		return nil
-	case <-time.After(30 * time.Minute):
-		return fmt.Errorf("Resizing node requests timed out after 30 minutes.")
+	case <-time.After(GKEResizeTimeout):
+		p.log.Err("Resize Requests timed out after %s.", GKEResizeTimeout)
+	}
+
+	lock.Lock()
+	defer lock.Unlock()
+
+	// Any node pools without a result did not complete the resize
+	for i, np := range nodePools {
+		if results[i] == nil {
+			results[i] = NewNodePoolResult(np, sizeFor(np), np.NodeCount(), fmt.Errorf("Resize request timed out after %s.", GKEResizeTimeout))
+		}
+	}
+
+	return append(NodePoolResults{}, results...)
+}
+
+// SetNodePoolSize sets the node count per zone, so the total nodes of a regional or multi-zonal node
+// pool are divided across its zones, rounding up. Node pools have an instance group for each zone.
+func gkeZoneNodeCount(nodes int32, zones int) int32 {
+	if zones <= 1 {
+		return nodes
+	}
+
+	return (nodes + int32(zones) - 1) / int32(zones)
+}
+
+// Counts the kubernetes nodes in each node pool using the node pool label
+func (p *GKEProvider) liveNodeCounts() (map[string]int32, error) {
+	nodes, err := p.kubernetes.CoreV1().Nodes().List(metav1.ListOptions{
+		LabelSelector: LabelGKENodePool,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	counts := make(map[string]int32)
+	for _, node := range nodes.Items {
+		counts[node.Labels[LabelGKENodePool]]++
+	}
+
+	return counts, nil
+}
+
+// Loads the resource labels set on the GKE cluster
+func (p *GKEProvider) clusterLabels() (map[string]string, error) {
+	cluster, err := p.clusterManager.GetCluster(context.TODO(), &container.GetClusterRequest{
+		ProjectId: p.metadata.GetProjectID(),
+		ClusterId: p.metadata.GetClusterID(),
+		Zone:      p.metadata.GetMasterZone(),
+	}, options...)
+	if err != nil {
+		return nil, err
 	}
+
+	return cluster.GetResourceLabels(), nil
+}
+
+// Applies the update func to the current cluster resource labels and sets the result on the cluster,
+// retrying while other cluster operations are in progress.
+func (p *GKEProvider) updateClusterLabels(update func(labels map[string]string)) error {
+	ctx := context.TODO()
+
+	projectID := p.metadata.GetProjectID()
+	zone := p.metadata.GetMasterZone()
+	clusterID := p.metadata.GetClusterID()
+
+	var err error
+	for retries := 0; retries < 10; retries++ {
+		if retries > 0 {
+			p.log.Log("Cluster operation already in queue, retrying...")
+			time.Sleep(30 * time.Second)
+		}
+
+		var cluster *container.Cluster
+		cluster, err = p.clusterManager.GetCluster(ctx, &container.GetClusterRequest{
+			ProjectId: projectID,
+			ClusterId: clusterID,
+			Zone:      zone,
+		}, options...)
+		if err != nil {
+			continue
+		}
+
+		labels := make(map[string]string)
+		for k, v := range cluster.GetResourceLabels() {
+			labels[k] = v
+		}
+		update(labels)
+
+		_, err = p.clusterManager.SetLabels(ctx, &container.SetLabelsRequest{
+			ProjectId:        projectID,
+			ClusterId:        clusterID,
+			Zone:             zone,
+			ResourceLabels:   labels,
+			LabelFingerprint: cluster.GetLabelFingerprint(),
+		}, options...)
+		if err == nil {
+			return nil
+		}
+	}
+
+	return err
 }
 
 func (p *GKEProvider) projectInfoFor(node *v1.Node) (project string, zone string, nodePool string) {
@@ -348,6 +636,50 @@ func (p *GKEProvider) projectInfoFor(node *v1.Node) (project string, zone string
 	return
 }
 
+// Returns the managed instance group of the node pool in the zone
+func (np *GKENodePool) instanceGroupFor(project, zone string) (string, bool) {
+	for _, group := range np.instanceGroups {
+		groupProject, groupZone, _ := gkeInstanceGroupInfo(group)
+		if groupProject == project && groupZone == zone {
+			return group, true
+		}
+	}
+
+	return "", false
+}
+
+// Parses the project, zone and name from a managed instance group url, ie:
+// https://www.googleapis.com/compute/v1/projects/<project>/zones/<zone>/instanceGroupManagers/<name>
+func gkeInstanceGroupInfo(url string) (project string, zone string, name string) {
+	props := strings.Split(url, "/")
+	for i := 0; i < len(props)-1; i++ {
+		switch props[i] {
+		case "projects":
+			project = props[i+1]
+		case "zones":
+			zone = props[i+1]
+		case "instanceGroupManagers":
+			name = props[i+1]
+		}
+	}
+
+	return
+}
+
+// Parses the instance name from the Node.Spec.ProviderID, ie: gce://<project>/<zone>/<instance>
+func gkeInstanceName(node *v1.Node) string {
+	id := node.Spec.ProviderID
+	return id[strings.LastIndex(id, "/")+1:]
+}
+
+func newGKEComputeService() (*compute.Service, error) {
+	if !file.FileExists(GKEAuthServiceAccount) {
+		return nil, fmt.Errorf("Failed to located service account file: %s", GKEAuthServiceAccount)
+	}
+
+	return compute.NewService(context.Background())
+}
+
 func newGKEClusterManager() (*gke.ClusterManagerClient, error) {
 	if !file.FileExists(GKEAuthServiceAccount) {
 		return nil, fmt.Errorf("Failed to located service account file: %s", GKEAuthServiceAccount)
@@ -362,3 +694,11 @@ func newGKEClusterManager() (*gke.ClusterManagerClient, error) {
 
 	return clusterManager, nil
 }
+
+func gkePreviousKeyFor(nodePool string) string {
+	return GKENodePoolPreviousKeyPrefix + nodePool
+}
+
+func gkeFlatRange(min, max, count int32) string {
+	return fmt.Sprintf("%d_%d_%d", min, max, count)
+}
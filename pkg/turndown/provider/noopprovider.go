@@ -0,0 +1,50 @@
+package provider
+
+import (
+	"fmt"
+
+	v1 "k8s.io/api/core/v1"
+)
+
+// ComputeProvider for clusters without node pool control (bare-metal, kind, on-prem). Turndown on
+// these clusters only flattens and suspends workloads, leaving the infrastructure untouched.
+type NoopProvider struct{}
+
+func NewNoopProvider() ComputeProvider {
+	return &NoopProvider{}
+}
+
+// IsWorkloadOnly returns true if the provider does not manage any infrastructure, and turndown should
+// only flatten and suspend workloads.
+func IsWorkloadOnly(provider ComputeProvider) bool {
+	_, ok := provider.(*NoopProvider)
+	return ok
+}
+
+func (p *NoopProvider) IsServiceAccountKey() bool {
+	return true
+}
+
+func (p *NoopProvider) IsTurndownNodePool() bool {
+	return false
+}
+
+func (p *NoopProvider) CreateSingletonNodePool() error {
+	return fmt.Errorf("Creating a Singleton Node not supported in workload-only mode!")
+}
+
+func (p *NoopProvider) GetNodePools() ([]NodePool, error) {
+	return []NodePool{}, nil
+}
+
+func (p *NoopProvider) GetPoolID(node *v1.Node) string {
+	return ""
+}
+
+func (p *NoopProvider) SetNodePoolSizes(nodePools []NodePool, size int32) NodePoolResults {
+	return NodePoolResults{}
+}
+
+func (p *NoopProvider) ResetNodePoolSizes(nodePools []NodePool) NodePoolResults {
+	return NodePoolResults{}
+}
@@ -11,8 +11,10 @@ import (
 
 	v1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 
 	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/dynamic"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/klog"
 )
@@ -38,8 +40,8 @@ type ComputeProvider interface {
 	CreateSingletonNodePool() error
 	GetNodePools() ([]NodePool, error)
 	GetPoolID(node *v1.Node) string
-	SetNodePoolSizes(nodePools []NodePool, size int32) error
-	ResetNodePoolSizes(nodePools []NodePool) error
+	SetNodePoolSizes(nodePools []NodePool, size int32) NodePoolResults
+	ResetNodePoolSizes(nodePools []NodePool) NodePoolResults
 }
 
 // NodePool contains a node pool identifier and the initial number of nodes
@@ -56,28 +58,144 @@ type NodePool interface {
 	Tags() map[string]string
 }
 
+// NodeRemover is implemented by ComputeProviders which can remove specific nodes from a NodePool. Partially
+// scaling down a NodePool removes the drained nodes, rather than leaving the provider to choose which nodes
+// to remove.
+type NodeRemover interface {
+	// Removes the nodes from the NodePool, reducing its size by the number of nodes removed. The previous
+	// range is stored, so ResetNodePoolSizes restores the NodePool.
+	RemoveNodes(nodePool NodePool, nodes []v1.Node) *NodePoolResult
+}
+
+// ZonalNodeRemover is implemented by NodeRemovers which size NodePools per zone, ie: regional or multi-zonal
+// GKE node pools. The target size of such a NodePool applies to the nodes of each zone, so the nodes to
+// remove are selected per zone.
+type ZonalNodeRemover interface {
+	NodeRemover
+
+	// Returns the zone of the node if the NodePool is sized per zone, or an empty string otherwise
+	NodePoolZoneFor(nodePool NodePool, node *v1.Node) string
+}
+
+// NodePoolOutcome is the result state of a resize operation on a single NodePool
+type NodePoolOutcome string
+
+const (
+	NodePoolResized NodePoolOutcome = "Resized"
+	NodePoolFailed  NodePoolOutcome = "Failed"
+	NodePoolSkipped NodePoolOutcome = "Skipped"
+)
+
+// NodePoolResult contains the requested and previous sizes of a NodePool resize operation
+// as well as the outcome and error if the resize failed.
+type NodePoolResult struct {
+	NodePool      NodePool
+	RequestedSize int32
+	PreviousSize  int32
+	Outcome       NodePoolOutcome
+	Error         error
+}
+
+// NewNodePoolResult creates a new result for a resize of the NodePool to the requested size. The
+// error determines the outcome of the operation.
+func NewNodePoolResult(nodePool NodePool, requested int32, previous int32, err error) *NodePoolResult {
+	outcome := NodePoolResized
+	if err != nil {
+		outcome = NodePoolFailed
+	}
+
+	return &NodePoolResult{
+		NodePool:      nodePool,
+		RequestedSize: requested,
+		PreviousSize:  previous,
+		Outcome:       outcome,
+		Error:         err,
+	}
+}
+
+// NodePoolResults is a list of results for each NodePool passed to a resize operation
+type NodePoolResults []*NodePoolResult
+
+// Resized returns the NodePools which were successfully resized.
+func (npr NodePoolResults) Resized() []NodePool {
+	return npr.withOutcome(NodePoolResized)
+}
+
+// Failed returns the NodePools which failed to resize and can be retried.
+func (npr NodePoolResults) Failed() []NodePool {
+	return npr.withOutcome(NodePoolFailed)
+}
+
+// Skipped returns the NodePools which were left as they are, ie: a missing previous range. Retrying
+// does not resize these.
+func (npr NodePoolResults) Skipped() []NodePool {
+	return npr.withOutcome(NodePoolSkipped)
+}
+
+// Err returns a single error containing all of the failed NodePool errors, or nil if
+// there were no failures.
+func (npr NodePoolResults) Err() error {
+	var errs []string
+	for _, r := range npr {
+		if r.Outcome == NodePoolFailed && r.Error != nil {
+			errs = append(errs, fmt.Sprintf("%s: %s", r.NodePool.Name(), r.Error.Error()))
+		}
+	}
+
+	if len(errs) == 0 {
+		return nil
+	}
+
+	return fmt.Errorf("Failed to resize %d node pool(s): [%s]", len(errs), strings.Join(errs, ", "))
+}
+
+func (npr NodePoolResults) withOutcome(outcome NodePoolOutcome) []NodePool {
+	pools := []NodePool{}
+	for _, r := range npr {
+		if r.Outcome == outcome {
+			pools = append(pools, r.NodePool)
+		}
+	}
+
+	return pools
+}
+
 var _ = klog.V(1)
 
-func NewProvider(client kubernetes.Interface) (ComputeProvider, error) {
+func NewProvider(client kubernetes.Interface, dynamicClient dynamic.Interface) (ComputeProvider, error) {
 	if metadata.OnGCE() {
 		return NewGKEProvider(client), nil
 	}
 
+	// Clusters managed by Cluster API are resized through their MachineDeployments regardless of
+	// the underlying infrastructure. Management clusters whose nodes are not managed by Cluster API
+	// use the provider of their nodes.
+	if IsCAPICluster(client) {
+		klog.V(2).Info("Found Cluster API resources, using CAPI Provider")
+		return NewCAPIProvider(client, dynamicClient)
+	}
+
 	nodes, err := client.CoreV1().Nodes().List(metav1.ListOptions{})
 	if err != nil {
 		return nil, err
 	}
+	if len(nodes.Items) == 0 {
+		return nil, errors.New("Failed to locate any kubernetes nodes")
+	}
 
 	provider := strings.ToLower(nodes.Items[0].Spec.ProviderID)
 	if strings.HasPrefix(provider, "aws") {
 		klog.V(2).Info("Found ProviderID starting with \"aws\", using AWS Provider")
 		return NewAWSProvider(client), nil
+	} else if strings.HasPrefix(provider, "digitalocean") {
+		klog.V(2).Info("Found ProviderID starting with \"digitalocean\", using DigitalOcean Provider")
+		return NewDOProvider(client), nil
 	} else if strings.HasPrefix(provider, "azure") {
 		klog.V(2).Info("Found ProviderID starting with \"azure\", using Azure Provider")
 		return nil, errors.New("Azure Not Supported")
 	} else {
-		klog.V(2).Info("Unsupported provider, falling back to default")
-		return nil, errors.New("Custom Not Supported")
+		klog.V(2).Info("Unsupported provider, falling back to workload-only turndown")
+		return NewNoopProvider(), nil
 	}
 }
 
@@ -94,3 +212,34 @@ func WaitUntilNodeCreated(client kubernetes.Interface, nodeLabelKey, nodeLabelVa
 		return false, err
 	})
 }
+
+// DiscoverResource locates the preferred version of the group serving the resource, if it exists.
+func DiscoverResource(kubernetes kubernetes.Interface, group string, resource string) (schema.GroupVersionResource, bool) {
+	groups, err := kubernetes.Discovery().ServerGroups()
+	if err != nil {
+		return schema.GroupVersionResource{}, false
+	}
+
+	for _, g := range groups.Groups {
+		if g.Name != group {
+			continue
+		}
+
+		resources, err := kubernetes.Discovery().ServerResourcesForGroupVersion(g.PreferredVersion.GroupVersion)
+		if err != nil {
+			return schema.GroupVersionResource{}, false
+		}
+
+		for _, r := range resources.APIResources {
+			if r.Name == resource {
+				return schema.GroupVersionResource{
+					Group:    group,
+					Version:  g.PreferredVersion.Version,
+					Resource: resource,
+				}, true
+			}
+		}
+	}
+
+	return schema.GroupVersionResource{}, false
+}
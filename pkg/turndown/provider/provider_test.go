@@ -0,0 +1,47 @@
+package provider
+
+import (
+	"testing"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes"
+	kubernetesfake "k8s.io/client-go/kubernetes/fake"
+)
+
+// Fixtures shared by the provider tests
+
+// Creates a node with the provided annotations
+func newTestNode(name string, annotations map[string]string) *v1.Node {
+	return &v1.Node{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        name,
+			Annotations: annotations,
+		},
+	}
+}
+
+// Creates a fake kubernetes client holding the provided nodes
+func newTestKubernetes(nodes ...*v1.Node) kubernetes.Interface {
+	objects := []runtime.Object{}
+	for _, node := range nodes {
+		objects = append(objects, node)
+	}
+
+	return kubernetesfake.NewSimpleClientset(objects...)
+}
+
+// Returns the node pools of the provider by name
+func testNodePoolsByName(t *testing.T, p ComputeProvider) map[string]NodePool {
+	pools, err := p.GetNodePools()
+	if err != nil {
+		t.Fatalf("Failed to get node pools: %s", err.Error())
+	}
+
+	byName := make(map[string]NodePool)
+	for _, np := range pools {
+		byName[np.Name()] = np
+	}
+	return byName
+}
@@ -26,7 +26,7 @@ func validateProvider(provider ComputeProvider, maxRetries int, done chan<- erro
 		}
 
 		if retries != (maxRetries - 1) {
-			klog.Infof("Retrying (%d remaining) in %d seconds...", maxRetries-retries-1, interval.Seconds)
+			klog.Infof("Retrying (%d remaining) in %d seconds...", maxRetries-retries-1, int(interval.Seconds()))
 			time.Sleep(interval)
 		}
 	}
@@ -11,8 +11,6 @@ import (
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
 	"k8s.io/apimachinery/pkg/util/wait"
 	"k8s.io/client-go/kubernetes"
-	"k8s.io/client-go/kubernetes/scheme"
-	typedcorev1 "k8s.io/client-go/kubernetes/typed/core/v1"
 	"k8s.io/client-go/tools/cache"
 	"k8s.io/client-go/tools/record"
 	"k8s.io/client-go/util/workqueue"
@@ -20,7 +18,6 @@ import (
 
 	"github.com/kubecost/cluster-turndown/pkg/apis/turndownschedule/v1alpha1"
 	clientset "github.com/kubecost/cluster-turndown/pkg/generated/clientset/versioned"
-	schedulescheme "github.com/kubecost/cluster-turndown/pkg/generated/clientset/versioned/scheme"
 	informers "github.com/kubecost/cluster-turndown/pkg/generated/informers/externalversions/turndownschedule/v1alpha1"
 	listers "github.com/kubecost/cluster-turndown/pkg/generated/listers/turndownschedule/v1alpha1"
 )
@@ -36,6 +33,15 @@ const (
 	CancelTurndownSuccess        = "CancelTurndownSuccess"
 	CancelTurndownSuccessMessage = "Successfully cancelled turndown"
 
+	SnoozeTurndownSuccess = "SnoozeTurndownSuccess"
+	SnoozeTurndownFailed  = "SnoozeTurndownFailed"
+
+	ActionTurndownSuccess = "ActionTurndownSuccess"
+	ActionTurndownFailed  = "ActionTurndownFailed"
+
+	RescheduleTurndownSuccess = "RescheduleTurndownSuccess"
+	RescheduleTurndownFailed  = "RescheduleTurndownFailed"
+
 	// ErrAlreadyScheduled is used as part of the Event 'reason' when a TurndownSchedule fails
 	// due to an existing turndown schedule.
 	ErrAlreadyScheduled = "ErrAlreadyScheduled"
@@ -68,14 +74,8 @@ func NewTurndownScheduleResourceController(
 	kubeclientset kubernetes.Interface,
 	clientset clientset.Interface,
 	scheduler *TurndownScheduler,
-	schedulesInformer informers.TurndownScheduleInformer) *TurndownScheduleResourceController {
-
-	utilruntime.Must(schedulescheme.AddToScheme(scheme.Scheme))
-
-	eventBroadcaster := record.NewBroadcaster()
-	eventBroadcaster.StartLogging(klog.Infof)
-	eventBroadcaster.StartRecordingToSink(&typedcorev1.EventSinkImpl{Interface: kubeclientset.CoreV1().Events("")})
-	recorder := eventBroadcaster.NewRecorder(scheme.Scheme, corev1.EventSource{Component: controllerAgentName})
+	schedulesInformer informers.TurndownScheduleInformer,
+	recorder record.EventRecorder) *TurndownScheduleResourceController {
 
 	controller := &TurndownScheduleResourceController{
 		kubeclientset:     kubeclientset,
@@ -215,9 +215,27 @@ func (c *TurndownScheduleResourceController) handle(key string) error {
 		return nil
 	}
 
-	// Check to see if there is an existing status/state before scheduling
+	// Apply a snooze or action requested through annotations on the scheduled turndown
+	if turndownSchedule.Status.State == ScheduleStateSuccess {
+		if _, ok := turndownSchedule.Annotations[KubecostTurnDownSnooze]; ok {
+			return c.trySnooze(turndownSchedule)
+		}
+
+		if _, ok := turndownSchedule.Annotations[KubecostTurnDownAction]; ok {
+			return c.tryAction(turndownSchedule)
+		}
+	}
+
+	// Check to see if there is an existing status/state before scheduling. Schedules with an edited spec
+	// are scheduled again.
 	if turndownSchedule.Status.State != "" {
-		return nil
+		if !isSpecChanged(turndownSchedule) {
+			return nil
+		}
+
+		if turndownSchedule.Status.State == ScheduleStateSuccess {
+			return c.tryReschedule(turndownSchedule)
+		}
 	}
 
 	err = c.trySchedule(turndownSchedule)
@@ -233,13 +251,25 @@ func (c *TurndownScheduleResourceController) handle(key string) error {
 func (c *TurndownScheduleResourceController) trySchedule(schedule *v1alpha1.TurndownSchedule) error {
 	scheduleCopy := schedule.DeepCopy()
 
+	var tds *Schedule
+	var err error
+
 	s := scheduleCopy.Spec
-	tds, err := c.scheduler.ScheduleTurndown(s.Start.Time, s.End.Time, s.Repeat)
+	switch s.Action {
+	case "":
+		tds, err = c.scheduler.ScheduleTurndown(s.Start.Time, s.End.Time, s.Repeat, NewNodePoolTargets(s.NodePoolTargets))
+	case TurndownJobTypeScaleDown:
+		tds, err = c.scheduler.ScheduleScaleDownNow(NewNodePoolTargets(s.NodePoolTargets))
+	default:
+		err = fmt.Errorf("Invalid action: %s", s.Action)
+	}
 
 	// Update the Schedule Status on Creation Here -- Other status changes are made by ScheduleStore
 	scheduleCopy.Status.LastUpdated = v1.NewTime(time.Now().UTC())
+	scheduleCopy.Status.ObservedGeneration = schedule.Generation
 	if err != nil {
 		scheduleCopy.Status.State = ScheduleStateFailed
+		setCondition(&scheduleCopy.Status, ConditionScheduled, corev1.ConditionFalse, ScheduleStateFailed, err.Error())
 	} else {
 		scheduleCopy.Status.State = ScheduleStateSuccess
 		WriteScheduleStatus(&scheduleCopy.Status, tds)
@@ -249,6 +279,66 @@ func (c *TurndownScheduleResourceController) trySchedule(schedule *v1alpha1.Turn
 	return err
 }
 
+// Tries to reschedule the turndown after the spec of a scheduled TurndownSchedule resource was edited. The
+// current schedule is kept if the new spec is invalid, and the failure is reported on the status.
+func (c *TurndownScheduleResourceController) tryReschedule(schedule *v1alpha1.TurndownSchedule) error {
+	// The schedule status is updated by the ScheduleStore, so work from the latest rather than the cache
+	latest, err := c.clientset.KubecostV1alpha1().TurndownSchedules().Get(schedule.Name, v1.GetOptions{})
+	if err != nil {
+		return err
+	}
+	if !isSpecChanged(latest) {
+		return nil
+	}
+
+	s := latest.Spec
+	if s.Action != "" {
+		err = RescheduleOnDemandErr
+	} else {
+		_, err = c.scheduler.Reschedule(s.Start.Time, s.End.Time, s.Repeat, NewNodePoolTargets(s.NodePoolTargets))
+	}
+
+	// Retry once the running job completes
+	if err == RescheduleWhileRunningErr {
+		return err
+	}
+
+	// Reload the status written by the ScheduleStore before observing the generation
+	if err == nil {
+		latest, err = c.clientset.KubecostV1alpha1().TurndownSchedules().Get(schedule.Name, v1.GetOptions{})
+		if err != nil {
+			return err
+		}
+	}
+
+	scheduleCopy := latest.DeepCopy()
+	scheduleCopy.Status.ObservedGeneration = latest.Generation
+	scheduleCopy.Status.LastUpdated = v1.NewTime(time.Now().UTC())
+	if err != nil {
+		setCondition(&scheduleCopy.Status, ConditionScheduled, corev1.ConditionTrue, RescheduleTurndownFailed, err.Error())
+	}
+
+	_, updateErr := c.clientset.KubecostV1alpha1().TurndownSchedules().UpdateStatus(scheduleCopy)
+	if updateErr != nil {
+		return updateErr
+	}
+
+	if err != nil {
+		c.recorder.Eventf(schedule, corev1.EventTypeWarning, RescheduleTurndownFailed, "Failed to apply spec changes, keeping the current schedule: %s", err.Error())
+		return nil
+	}
+
+	c.recorder.Event(schedule, corev1.EventTypeNormal, RescheduleTurndownSuccess, "Successfully rescheduled turndown")
+	return nil
+}
+
+// Determines whether the spec was edited since it was last scheduled. Schedules created before the
+// generation was observed are not rescheduled.
+func isSpecChanged(schedule *v1alpha1.TurndownSchedule) bool {
+	observed := schedule.Status.ObservedGeneration
+	return observed != 0 && schedule.Generation != observed
+}
+
 // Tries to cancel a turndown schedule based on the soft deletion of a TurndownSchedule resource
 // This method will also finalize the resource if cancelling succeeds.
 func (c *TurndownScheduleResourceController) tryCancel(schedule *v1alpha1.TurndownSchedule) error {
@@ -269,6 +359,72 @@ func (c *TurndownScheduleResourceController) tryCancel(schedule *v1alpha1.Turndo
 	return c.clearFinalizer(scheduleCopy)
 }
 
+// Tries to snooze the next scale down using the snooze annotations on a TurndownSchedule resource. The
+// annotations are removed first so the snooze is only applied once, and failures are recorded as events.
+func (c *TurndownScheduleResourceController) trySnooze(schedule *v1alpha1.TurndownSchedule) error {
+	scheduleCopy := schedule.DeepCopy()
+
+	value := scheduleCopy.Annotations[KubecostTurnDownSnooze]
+	by := scheduleCopy.Annotations[KubecostTurnDownSnoozedBy]
+	reason := scheduleCopy.Annotations[KubecostTurnDownSnoozeReason]
+
+	delete(scheduleCopy.Annotations, KubecostTurnDownSnooze)
+	delete(scheduleCopy.Annotations, KubecostTurnDownSnoozedBy)
+	delete(scheduleCopy.Annotations, KubecostTurnDownSnoozeReason)
+
+	_, err := c.clientset.KubecostV1alpha1().TurndownSchedules().Update(scheduleCopy)
+	if err != nil {
+		return err
+	}
+
+	if by == "" {
+		by = "annotation"
+	}
+
+	duration, err := time.ParseDuration(value)
+	if err == nil {
+		_, err = c.scheduler.Snooze(duration, maxSnoozeFor(&schedule.Spec), by, reason)
+	}
+	if err != nil {
+		c.recorder.Eventf(schedule, corev1.EventTypeWarning, SnoozeTurndownFailed, "Failed to snooze turndown: %s", err.Error())
+		return nil
+	}
+
+	c.recorder.Eventf(schedule, corev1.EventTypeNormal, SnoozeTurndownSuccess, "Snoozed turndown by %s for %s", duration, by)
+	return nil
+}
+
+// Tries to run the scale down or scale up requested by the action annotation on a TurndownSchedule resource
+// immediately. The annotation is removed first so the action only runs once, and failures are recorded as
+// events.
+func (c *TurndownScheduleResourceController) tryAction(schedule *v1alpha1.TurndownSchedule) error {
+	scheduleCopy := schedule.DeepCopy()
+
+	action := scheduleCopy.Annotations[KubecostTurnDownAction]
+	delete(scheduleCopy.Annotations, KubecostTurnDownAction)
+
+	_, err := c.clientset.KubecostV1alpha1().TurndownSchedules().Update(scheduleCopy)
+	if err != nil {
+		return err
+	}
+
+	switch action {
+	case TurndownJobTypeScaleDown:
+		err = c.scheduler.ScaleDownNow()
+	case TurndownJobTypeScaleUp:
+		err = c.scheduler.ScaleUpNow()
+	default:
+		err = fmt.Errorf("Invalid action: %s", action)
+	}
+	if err != nil {
+		c.recorder.Eventf(schedule, corev1.EventTypeWarning, ActionTurndownFailed, "Failed to run %s: %s", action, err.Error())
+		return nil
+	}
+
+	c.recorder.Eventf(schedule, corev1.EventTypeNormal, ActionTurndownSuccess, "Started %s", action)
+	return nil
+}
+
 // Clear Finalizers handles updating the resource to clear the specific turndown finalizer for our
 // TurndownSchedule resource. This allows us to effectively finalize deletion for TurndownSchedules
 func (c *TurndownScheduleResourceController) clearFinalizer(schedule *v1alpha1.TurndownSchedule) error {
@@ -332,7 +488,9 @@ func runPruneCompletedSchedules(interval time.Duration, maxAge time.Duration, cl
 	}(client, stopCh)
 }
 
-// Clears failed or completed schedules over a specific age
+// Clears failed or completed schedules over a specific age. TurndownRuns are kept until pruned by their
+// own retention. Runs recorded with an owner reference to the schedule by earlier versions are orphaned
+// rather than deleted.
 func clearCompletedSchedules(client clientset.Interface, maxAge time.Duration) {
 	schedules, err := client.KubecostV1alpha1().TurndownSchedules().List(v1.ListOptions{})
 	if err != nil {
@@ -341,13 +499,16 @@ func clearCompletedSchedules(client clientset.Interface, maxAge time.Duration) {
 
 	// LastUpdated must be after now-maxAge
 	mustBeAfter := time.Now().UTC().Add(-maxAge)
+	orphan := v1.DeletePropagationOrphan
 
 	// Find schedules to prune
 	for _, schedule := range schedules.Items {
 		if schedule.Status.State != ScheduleStateSuccess {
 			lastUpdated := schedule.Status.LastUpdated.Time
 			if lastUpdated.Before(mustBeAfter) {
-				client.KubecostV1alpha1().TurndownSchedules().Delete(schedule.Name, &v1.DeleteOptions{})
+				client.KubecostV1alpha1().TurndownSchedules().Delete(schedule.Name, &v1.DeleteOptions{
+					PropagationPolicy: &orphan,
+				})
 			}
 		}
 	}
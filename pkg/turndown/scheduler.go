@@ -34,6 +34,7 @@ type JobScheduler interface {
 	NextScheduledTimeFor(id string) (next time.Time, ok bool)
 	SetJobCompleteHandler(handler JobCompleteHandler)
 	IsRunning(jobID string) bool
+	Stop()
 }
 
 type SimpleJob struct {
@@ -49,6 +50,8 @@ type SimpleJobScheduler struct {
 	lock        *sync.Mutex
 	runningJobs *async.ConcurrentStringSet
 	jobComplete JobCompleteHandler
+	running     *sync.WaitGroup
+	stopped     bool
 }
 
 func NewSimpleScheduler() JobScheduler {
@@ -56,6 +59,7 @@ func NewSimpleScheduler() JobScheduler {
 		jobs:        make(map[string]*SimpleJob),
 		lock:        new(sync.Mutex),
 		runningJobs: async.NewConcurrentStringSet(),
+		running:     new(sync.WaitGroup),
 	}
 }
 
@@ -108,6 +112,30 @@ func (sjs *SimpleJobScheduler) IsRunning(jobID string) bool {
 	return sjs.runningJobs.Contains(jobID)
 }
 
+// Stops any scheduled jobs from starting, then waits for the running jobs and their job complete
+// handlers to finish.
+func (sjs *SimpleJobScheduler) Stop() {
+	sjs.lock.Lock()
+	sjs.stopped = true
+	sjs.lock.Unlock()
+
+	sjs.running.Wait()
+}
+
+// Marks the job as running unless the scheduler was stopped
+func (sjs *SimpleJobScheduler) startJob(id string) bool {
+	sjs.lock.Lock()
+	defer sjs.lock.Unlock()
+
+	if sjs.stopped {
+		return false
+	}
+
+	sjs.running.Add(1)
+	sjs.runningJobs.Add(id)
+	return true
+}
+
 // Looks up a job by identifier.
 func (sjs *SimpleJobScheduler) jobFor(id string) (job *SimpleJob, ok bool) {
 	sjs.lock.Lock()
@@ -159,6 +187,7 @@ func (sjs *SimpleJobScheduler) scheduleJob(ctx context.Context, job *SimpleJob)
 	remaining := job.next.UTC().Sub(time.Now().UTC())
 	go func() {
 		var isCancelled bool = false
+		var isStarted bool = false
 		var err error = nil
 
 		// Defer the job removal and jobComplete execution to ensure that they do
@@ -166,6 +195,9 @@ func (sjs *SimpleJobScheduler) scheduleJob(ctx context.Context, job *SimpleJob)
 		// scheduled job
 		defer func() {
 			defer sjs.runningJobs.Remove(job.id)
+			if isStarted {
+				defer sjs.running.Done()
+			}
 
 			sjs.removeJob(job.id)
 			if isCancelled {
@@ -181,7 +213,11 @@ func (sjs *SimpleJobScheduler) scheduleJob(ctx context.Context, job *SimpleJob)
 
 		select {
 		case <-time.After(remaining):
-			sjs.runningJobs.Add(job.id)
+			if isStarted = sjs.startJob(job.id); !isStarted {
+				isCancelled = true
+				klog.V(1).Infof("Scheduler stopped before job could run: %s", job.id)
+				return
+			}
 			err = job.job()
 		case <-ctx.Done():
 			isCancelled = true
@@ -12,6 +12,7 @@ import (
 	"github.com/kubecost/cluster-turndown/pkg/apis/turndownschedule/v1alpha1"
 	clientset "github.com/kubecost/cluster-turndown/pkg/generated/clientset/versioned"
 
+	corev1 "k8s.io/api/core/v1"
 	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
@@ -23,6 +24,15 @@ type Schedule struct {
 	ScaleUpID         string            `json:"scaleUpID"`
 	ScaleUpTime       time.Time         `json:"scaleUpTime"`
 	ScaleUpMetadata   map[string]string `json:"scaleUpMetadata"`
+	ScaledDownPools   []string          `json:"scaledDownPools,omitempty"`
+	ScaleDownReason   string            `json:"scaleDownReason,omitempty"`
+	WakeUntil         time.Time         `json:"wakeUntil,omitempty"`
+	SnoozedBy         string            `json:"snoozedBy,omitempty"`
+	SnoozeReason      string            `json:"snoozeReason,omitempty"`
+	InterruptedJob    string            `json:"interruptedJob,omitempty"`
+	CompletedSteps    []string          `json:"completedSteps,omitempty"`
+	Running           string            `json:"running,omitempty"`
+	LastRun           *ScheduleRun      `json:"lastRun,omitempty"`
 }
 
 // Persistent Schedule Storage interface for storing and retrieving a single stored schedule.
@@ -32,6 +42,10 @@ type ScheduleStore interface {
 	Update(schedule *Schedule) error
 	Complete()
 	Clear()
+
+	// Reference to the resource persisting the schedule, which events are recorded on. Returns nil
+	// if the schedule is not persisted as a resource.
+	Reference() *corev1.ObjectReference
 }
 
 type KubernetesScheduleStore struct {
@@ -56,6 +70,14 @@ func WriteSchedule(schedule *Schedule, status *v1alpha1.TurndownScheduleStatus)
 	schedule.ScaleUpMetadata = status.ScaleUpMetadata
 	schedule.ScaleDownTime = status.ScaleDownTime.Time
 	schedule.ScaleUpTime = status.ScaleUpTime.Time
+	schedule.ScaledDownPools = status.ScaledDownPools
+	schedule.ScaleDownReason = status.ScaleDownReason
+	schedule.WakeUntil = status.WakeUntil.Time
+	schedule.SnoozedBy = status.SnoozedBy
+	schedule.SnoozeReason = status.SnoozeReason
+	schedule.InterruptedJob = status.InterruptedJob
+	schedule.CompletedSteps = status.CompletedSteps
+	schedule.LastRun = lastRunFor(status)
 }
 
 func WriteScheduleStatus(status *v1alpha1.TurndownScheduleStatus, schedule *Schedule) {
@@ -70,7 +92,17 @@ func WriteScheduleStatus(status *v1alpha1.TurndownScheduleStatus, schedule *Sche
 	status.ScaleUpMetadata = schedule.ScaleUpMetadata
 	status.ScaleDownTime = v1.NewTime(schedule.ScaleDownTime)
 	status.ScaleUpTime = v1.NewTime(schedule.ScaleUpTime)
+	status.ScaledDownPools = schedule.ScaledDownPools
+	status.ScaleDownReason = schedule.ScaleDownReason
+	status.WakeUntil = v1.NewTime(schedule.WakeUntil)
+	status.SnoozedBy = schedule.SnoozedBy
+	status.SnoozeReason = schedule.SnoozeReason
+	status.InterruptedJob = schedule.InterruptedJob
+	status.CompletedSteps = schedule.CompletedSteps
 	status.LastUpdated = v1.NewTime(time.Now().UTC())
+
+	writeConditions(status, schedule)
+	appendHistory(status, schedule.LastRun)
 }
 
 func (kss *KubernetesScheduleStore) GetSchedule() (*Schedule, error) {
@@ -127,6 +159,7 @@ func (kss *KubernetesScheduleStore) Complete() {
 			tdCopy := td.DeepCopy()
 			tdCopy.Status.State = ScheduleStateCompleted
 			tdCopy.Status.LastUpdated = v1.NewTime(time.Now().UTC())
+			setCondition(&tdCopy.Status, ConditionScheduled, corev1.ConditionFalse, ScheduleStateCompleted, "Turndown schedule has completed")
 
 			kss.client.KubecostV1alpha1().TurndownSchedules().UpdateStatus(tdCopy)
 			return
@@ -145,6 +178,7 @@ func (kss *KubernetesScheduleStore) Clear() {
 			tdCopy := td.DeepCopy()
 			tdCopy.Status.State = ScheduleStateCompleted
 			tdCopy.Status.LastUpdated = v1.NewTime(time.Now().UTC())
+			setCondition(&tdCopy.Status, ConditionScheduled, corev1.ConditionFalse, ScheduleStateCompleted, "Turndown schedule has completed")
 
 			kss.client.KubecostV1alpha1().TurndownSchedules().UpdateStatus(tdCopy)
 			return
@@ -152,6 +186,26 @@ func (kss *KubernetesScheduleStore) Clear() {
 	}
 }
 
+func (kss *KubernetesScheduleStore) Reference() *corev1.ObjectReference {
+	tds, err := kss.client.KubecostV1alpha1().TurndownSchedules().List(v1.ListOptions{})
+	if err != nil {
+		return nil
+	}
+
+	for _, td := range tds.Items {
+		if td.Status.State == ScheduleStateSuccess {
+			return &corev1.ObjectReference{
+				Kind:       "TurndownSchedule",
+				APIVersion: v1alpha1.SchemeGroupVersion.String(),
+				Name:       td.Name,
+				UID:        td.UID,
+			}
+		}
+	}
+
+	return nil
+}
+
 // Disk based implementation of persistent schedule storage.
 type DiskScheduleStore struct {
 	file string
@@ -213,3 +267,7 @@ func (dss *DiskScheduleStore) Clear() {
 
 	os.Remove(dss.file)
 }
+
+func (dss *DiskScheduleStore) Reference() *corev1.ObjectReference {
+	return nil
+}
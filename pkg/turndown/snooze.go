@@ -0,0 +1,31 @@
+package turndown
+
+import (
+	"time"
+
+	"github.com/kubecost/cluster-turndown/pkg/apis/turndownschedule/v1alpha1"
+)
+
+const (
+	// Annotations on a TurndownSchedule requesting the next scale down be postponed. The annotations are
+	// removed once the snooze is applied.
+	KubecostTurnDownSnooze       = "kubecost.kubernetes.io/snooze"
+	KubecostTurnDownSnoozedBy    = "kubecost.kubernetes.io/snoozed-by"
+	KubecostTurnDownSnoozeReason = "kubecost.kubernetes.io/snooze-reason"
+)
+
+// SnoozeRequest is the POST encoding used to postpone the next scale down
+type SnoozeRequest struct {
+	Duration  string `json:"duration"`
+	SnoozedBy string `json:"snoozedBy,omitempty"`
+	Reason    string `json:"reason,omitempty"`
+}
+
+// Determines the maximum postponement allowed by the snooze policy on the schedule spec
+func maxSnoozeFor(spec *v1alpha1.TurndownScheduleSpec) time.Duration {
+	if spec == nil || spec.SnoozePolicy == nil || spec.SnoozePolicy.MaxDuration.Duration <= 0 {
+		return DefaultMaxSnooze
+	}
+
+	return spec.SnoozePolicy.MaxDuration.Duration
+}
@@ -0,0 +1,36 @@
+package strategy
+
+import (
+	"fmt"
+
+	v1 "k8s.io/api/core/v1"
+)
+
+// NoopTurndownStrategy is used when turndown only flattens workloads. No nodes are removed, so the
+// turndown pod does not need to move to a target host node.
+type NoopTurndownStrategy struct{}
+
+func NewNoopTurndownStrategy() TurndownStrategy {
+	return &NoopTurndownStrategy{}
+}
+
+// There are no host node updates to reverse.
+func (nts *NoopTurndownStrategy) IsReversible() bool {
+	return false
+}
+
+func (nts *NoopTurndownStrategy) TaintKey() string {
+	return ""
+}
+
+func (nts *NoopTurndownStrategy) CreateOrGetHostNode() (*v1.Node, error) {
+	return nil, fmt.Errorf("NoopTurndownStrategy does not use a host node.")
+}
+
+func (nts *NoopTurndownStrategy) UpdateDNS() error {
+	return nil
+}
+
+func (nts *NoopTurndownStrategy) ReverseHostNode() error {
+	return nil
+}
@@ -1,7 +1,11 @@
 package turndown
 
 import (
+	"fmt"
 	"os"
+	"sort"
+	"strings"
+	"time"
 
 	"github.com/kubecost/cluster-turndown/pkg/logging"
 	"github.com/kubecost/cluster-turndown/pkg/turndown/patcher"
@@ -12,10 +16,17 @@ import (
 	v1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
+	"k8s.io/client-go/dynamic"
 	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/record"
 	"k8s.io/klog"
 )
 
+const (
+	NodePoolResizeRetries    = 3
+	NodePoolResizeRetryDelay = 30 * time.Second
+)
+
 var (
 	KubecostFlattenerOmit = []string{"kube-dns", "kube-dns-autoscaler"}
 )
@@ -30,6 +41,10 @@ type TurndownManager interface {
 	// or not
 	IsRunningOnTurndownNode() (bool, error)
 
+	// Whether or not the turndown deployment has been moved to the turndown node while
+	// the current pod is still running elsewhere, and will be replaced
+	IsMovingToTurndownNode() (bool, error)
+
 	// Prepares the turndown environment by creating or selecting a target host node,
 	// applying specific labeling to that host node such that we can run our turndown
 	// logic from a "safe-from-turndown" node
@@ -39,30 +54,71 @@ type TurndownManager interface {
 	ResetTurndownEnvironment() error
 
 	// Scales down the cluster leaving the single small node pool running the scheduled
-	// scale up
-	ScaleDownCluster() error
+	// scale up. Node pools with a target are scaled down to the target size rather than 0.
+	ScaleDownCluster(targets NodePoolTargets) error
 
 	// Scales back up the cluster
 	ScaleUpCluster() error
+
+	// The names of the node pools which are currently scaled down by turndown
+	ScaledDownNodePools() []string
+
+	// The steps of the current scale down or scale up which have completed. An interrupted
+	// scale down or scale up uses these to resume where it left off.
+	CompletedSteps() []string
+
+	// Restores the completed steps of an interrupted scale down or scale up. Passing nil
+	// clears the steps once a scale down or scale up completes.
+	ResumeSteps(steps []string)
+
+	// Sets the TurndownSchedule to record events on for each step of a scale down or scale up
+	SetScheduleReference(ref *v1.ObjectReference)
+
+	// Sets the report to record the result of each step of a scale down or scale up on, along
+	// with the nodes and workloads touched. Passing nil stops recording.
+	SetRunReport(report *RunReport)
 }
 
+const (
+	// Steps recorded by the turndown manager as a scale down or scale up completes them
+	TurndownStepFlatten = "flatten"
+	TurndownStepDrain   = "drain/"
+	TurndownStepResize  = "resize"
+	TurndownStepRestore = "restore"
+
+	// Night pools are scaled up on scale down, and reset on scale up
+	TurndownStepNightPools = "night-pools"
+)
+
 type KubernetesTurndownManager struct {
 	client      kubernetes.Interface
+	dynamic     dynamic.Interface
 	provider    provider.ComputeProvider
 	strategy    strategy.TurndownStrategy
 	currentNode string
 	autoScaling *bool
 	nodePools   []provider.NodePool
+	steps       []string
+	stopCh      <-chan struct{}
+	recorder    record.EventRecorder
+	schedule    *v1.ObjectReference
+	report      *RunReport
 	log         logging.NamedLogger
 }
 
-func NewKubernetesTurndownManager(client kubernetes.Interface, provider provider.ComputeProvider, strategy strategy.TurndownStrategy, currentNode string) TurndownManager {
+// Creates a new KubernetesTurndownManager. Once stopCh is closed, scale downs and scale ups stop
+// between steps and return InterruptedErr. Each step is recorded as an event on the TurndownSchedule,
+// as well as the affected nodes and workloads.
+func NewKubernetesTurndownManager(client kubernetes.Interface, dynamic dynamic.Interface, provider provider.ComputeProvider, strategy strategy.TurndownStrategy, currentNode string, stopCh <-chan struct{}, recorder record.EventRecorder) TurndownManager {
 	return &KubernetesTurndownManager{
 		client:      client,
+		dynamic:     dynamic,
 		provider:    provider,
 		strategy:    strategy,
 		currentNode: currentNode,
 		autoScaling: nil,
+		stopCh:      stopCh,
+		recorder:    recorder,
 		log:         logging.NamedLogger("Turndown"),
 	}
 }
@@ -71,7 +127,84 @@ func (ktdm *KubernetesTurndownManager) IsScaledDown() bool {
 	return ktdm.nodePools == nil || len(ktdm.nodePools) == 0
 }
 
+func (ktdm *KubernetesTurndownManager) ScaledDownNodePools() []string {
+	return nodePoolNames(ktdm.nodePools)
+}
+
+func nodePoolNames(nodePools []provider.NodePool) []string {
+	names := []string{}
+	for _, np := range nodePools {
+		names = append(names, np.Name())
+	}
+
+	return names
+}
+
+func (ktdm *KubernetesTurndownManager) CompletedSteps() []string {
+	steps := make([]string, len(ktdm.steps))
+	copy(steps, ktdm.steps)
+	return steps
+}
+
+func (ktdm *KubernetesTurndownManager) ResumeSteps(steps []string) {
+	ktdm.steps = steps
+}
+
+// Whether or not the step was completed before the current scale down or scale up was interrupted
+func (ktdm *KubernetesTurndownManager) hasCompleted(step string) bool {
+	for _, s := range ktdm.steps {
+		if s == step {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (ktdm *KubernetesTurndownManager) completeStep(step string) {
+	ktdm.steps = append(ktdm.steps, step)
+}
+
+func (ktdm *KubernetesTurndownManager) SetScheduleReference(ref *v1.ObjectReference) {
+	ktdm.schedule = ref
+}
+
+func (ktdm *KubernetesTurndownManager) SetRunReport(report *RunReport) {
+	ktdm.report = report
+}
+
+// Creates a Flattener which records the workloads it updates on the current run report
+func (ktdm *KubernetesTurndownManager) newFlattener() *Flattener {
+	flattener := NewFlattener(ktdm.client, KubecostFlattenerOmit, ktdm.recorder)
+	flattener.report = ktdm.report
+	return flattener
+}
+
+// Records an event on the TurndownSchedule, if set
+func (ktdm *KubernetesTurndownManager) recordEvent(eventType string, reason string, messageFmt string, args ...interface{}) {
+	if ktdm.schedule == nil {
+		return
+	}
+
+	ktdm.recorder.Eventf(ktdm.schedule, eventType, reason, messageFmt, args...)
+}
+
+// Whether or not the turndown pod is shutting down
+func (ktdm *KubernetesTurndownManager) isStopping() bool {
+	select {
+	case <-ktdm.stopCh:
+		return true
+	default:
+		return false
+	}
+}
+
 func (ktdm *KubernetesTurndownManager) IsRunningOnTurndownNode() (bool, error) {
+	// Workload-only turndown does not remove any nodes, so any node is safe to run on
+	if provider.IsWorkloadOnly(ktdm.provider) {
+		return true, nil
+	}
+
 	nodeList, err := ktdm.client.CoreV1().Nodes().List(metav1.ListOptions{
 		LabelSelector: "cluster-turndown-node=true",
 	})
@@ -87,7 +220,36 @@ func (ktdm *KubernetesTurndownManager) IsRunningOnTurndownNode() (bool, error) {
 	return result, nil
 }
 
+func (ktdm *KubernetesTurndownManager) IsMovingToTurndownNode() (bool, error) {
+	deployment, err := ktdm.client.AppsV1().Deployments(turndownNamespace()).Get(turndownDeployment(), metav1.GetOptions{})
+	if err != nil {
+		return false, err
+	}
+
+	if deployment.Spec.Template.Spec.NodeSelector["cluster-turndown-node"] != "true" {
+		return false, nil
+	}
+
+	isOnNode, err := ktdm.IsRunningOnTurndownNode()
+	if err != nil {
+		return false, err
+	}
+
+	return !isOnNode, nil
+}
+
 func (ktdm *KubernetesTurndownManager) PrepareTurndownEnvironment() error {
+	err := ktdm.prepareTurndownEnvironment()
+	if err != nil {
+		ktdm.recordEvent(v1.EventTypeWarning, EnvironmentPrepareFailed, "Failed to prepare turndown environment: %s", err.Error())
+		return err
+	}
+
+	ktdm.recordEvent(v1.EventTypeNormal, EnvironmentPrepared, "Prepared turndown environment. Turndown will move to the host node.")
+	return nil
+}
+
+func (ktdm *KubernetesTurndownManager) prepareTurndownEnvironment() error {
 	ktdm.log.Log("Creating or Getting the Target Host Node...")
 	_, err := ktdm.strategy.CreateOrGetHostNode()
 	if err != nil {
@@ -105,17 +267,9 @@ func (ktdm *KubernetesTurndownManager) PrepareTurndownEnvironment() error {
 		return err
 	}
 
-	// Locate turndown namespace -- default to turndown
-	ns := os.Getenv("TURNDOWN_NAMESPACE")
-	if ns == "" {
-		ns = "turndown"
-	}
+	ns := turndownNamespace()
 
-	// Locate deployment name -- default to cluster-turndown
-	deploymentName := os.Getenv("TURNDOWN_DEPLOYMENT")
-	if deploymentName == "" {
-		deploymentName = "cluster-turndown"
-	}
+	deploymentName := turndownDeployment()
 
 	ktdm.log.Log("Applying Tolerations and Node Selector to turndown deployment...")
 
@@ -145,7 +299,7 @@ func (ktdm *KubernetesTurndownManager) PrepareTurndownEnvironment() error {
 	return nil
 }
 
-func (ktdm *KubernetesTurndownManager) ScaleDownCluster() error {
+func (ktdm *KubernetesTurndownManager) ScaleDownCluster(targets NodePoolTargets) error {
 	ktdm.log.Log("Scaling Down Cluster Now")
 
 	// 1. Start by finding all the nodes that Kubernetes is using
@@ -169,30 +323,101 @@ func (ktdm *KubernetesTurndownManager) ScaleDownCluster() error {
 		pools[np.Name()] = np
 	}
 
+	// Workload-only turndown has no node pools to resize, so flattening is the only way to
+	// reduce the workloads running on the cluster
+	workloadOnly := provider.IsWorkloadOnly(ktdm.provider)
+
+	// When night pools are configured, swap the day pools for the night pools rather than
+	// turning down the entire cluster
+	if !workloadOnly {
+		nightSwap, err := LoadNightSwapConfig(ktdm.client)
+		if err != nil {
+			ktdm.log.Err("Failed to load night swap configuration: %s", err.Error())
+			return err
+		}
+		if nightSwap != nil {
+			return ktdm.swapToNightPools(nightSwap, nodes.Items, nodePools)
+		}
+	}
+
 	// If this cluster has autoscaling nodes, we consider the entire cluster
 	// autoscaling. Run Flatten on the cluster to reduce deployments and daemonsets
 	// to 0 replicas. Otherwise, just suspend cron jobs
-	flattener := NewFlattener(ktdm.client, KubecostFlattenerOmit)
-	if isAutoScalingCluster {
+	flattener := ktdm.newFlattener()
+	if workloadOnly {
+		ktdm.log.Log("Workload-only turndown. Flattening Cluster...")
+		isAutoScalingCluster = true
+	} else if isAutoScalingCluster {
 		ktdm.log.Log("Found Cluster-AutoScaler. Flattening Cluster...")
+	}
+
+	// Karpenter will provision new nodes for any pending pods, so disable provisioning prior to
+	// flattening. Karpenter nodes are treated like autoscaling nodes.
+	var karpenter *Karpenter
+	var hasKarpenter bool
+	if !workloadOnly {
+		karpenter, hasKarpenter = NewKarpenter(ktdm.client, ktdm.dynamic)
+	}
+	if hasKarpenter {
+		ktdm.log.Log("Found Karpenter. Disabling Provisioning and Flattening Cluster...")
+		isAutoScalingCluster = true
 
+		err := karpenter.DisableProvisioning()
+		if err != nil {
+			ktdm.log.Err("Failed to disable Karpenter provisioning: %s", err.Error())
+			return err
+		}
+	}
+
+	if ktdm.hasCompleted(TurndownStepFlatten) {
+		ktdm.log.Log("Cluster was flattened before interruption. Skipping.")
+	} else if isAutoScalingCluster {
+
+		start := time.Now()
 		err := flattener.Flatten()
+		ktdm.report.AddStep(TurndownStepFlatten, start, err)
 		if err != nil {
 			klog.V(1).Infof("Failed to flatten cluster: %s", err.Error())
+			ktdm.recordEvent(v1.EventTypeWarning, FlattenFailed, "Failed to flatten cluster: %s", err.Error())
 			return err
 		}
+		ktdm.completeStep(TurndownStepFlatten)
+		ktdm.recordEvent(v1.EventTypeNormal, WorkloadsFlattened, "Flattened deployments and daemonsets, and suspended cron jobs")
 	} else {
 		ktdm.log.Log("Suspending all jobs...")
 
+		start := time.Now()
 		err := flattener.SuspendJobs()
+		ktdm.report.AddStep(TurndownStepFlatten, start, err)
 		if err != nil {
 			klog.V(1).Infof("Failed to suspend jobs: %s", err.Error())
+			ktdm.recordEvent(v1.EventTypeWarning, FlattenFailed, "Failed to suspend cron jobs: %s", err.Error())
 			return err
 		}
+		ktdm.completeStep(TurndownStepFlatten)
+		ktdm.recordEvent(v1.EventTypeNormal, JobsSuspended, "Suspended cron jobs")
+	}
+
+	// Workload-only turndown does not drain nodes or resize node pools
+	if workloadOnly {
+		ktdm.nodePools = nil
+		ktdm.autoScaling = &isAutoScalingCluster
+		return nil
+	}
+
+	// Now that the cluster is flattened, remove the nodes launched by Karpenter
+	if hasKarpenter {
+		ktdm.log.Log("Deleting Karpenter Nodes...")
+
+		err := karpenter.DeleteNodes(ktdm.currentNode)
+		if err != nil {
+			ktdm.log.Err("Failed to delete Karpenter nodes: %s", err.Error())
+		}
 	}
 
 	// 3. Drain a node if it is not the current node and is not part of an autoscaling pool.
 	var currentNodePoolID string
+	poolNodes := make(map[string][]v1.Node)
 	for _, n := range nodes.Items {
 		poolID := ktdm.provider.GetPoolID(&n)
 
@@ -201,6 +426,10 @@ func (ktdm *KubernetesTurndownManager) ScaleDownCluster() error {
 			continue
 		}
 
+		if hasKarpenter && karpenter.IsKarpenterNode(n.Labels) {
+			continue
+		}
+
 		pool, ok := pools[poolID]
 		if !ok {
 			ktdm.log.Err("Failed to locate pool id: %s in pools map.", poolID)
@@ -211,32 +440,85 @@ func (ktdm *KubernetesTurndownManager) ScaleDownCluster() error {
 			continue
 		}
 
-		draininator := NewDraininator(ktdm.client, n.Name)
-		err = draininator.Drain()
-		if err != nil {
-			ktdm.log.Err("Failed: %s - Error: %s", n.Name, err.Error())
+		poolNodes[poolID] = append(poolNodes[poolID], n)
+	}
+
+	// Node pools with a target size only drain the nodes that will be removed, if the provider can
+	// remove those specific nodes. Otherwise, the provider chooses which nodes to remove, so the entire
+	// node pool is drained.
+	remover, canRemove := ktdm.provider.(provider.NodeRemover)
+	removals := make(map[string][]v1.Node)
+	for poolID, pn := range poolNodes {
+		toDrain := pn
+		if size := targets.SizeFor(pools[poolID]); size > 0 && canRemove {
+			toDrain = ktdm.nodesToRemoveFor(pools[poolID], pn, int(size))
+			removals[poolID] = toDrain
+		}
+
+		for _, n := range toDrain {
+			err = ktdm.drainNode(n.Name)
+			if err == InterruptedErr {
+				return err
+			}
 		}
 	}
 
-	// 4. Filter out the current node pool holding the current node and/or autoscaling
+	// Resizing is left for the next pod once shutting down, as the node pools may not finish
+	// resizing before the pod is killed
+	if ktdm.isStopping() {
+		return InterruptedErr
+	}
+
+	// 4. Filter out the current node pool holding the current node and/or autoscaling, and any
+	// node pools already at their target size
 	targetPools := []provider.NodePool{}
 	for _, np := range nodePools {
 		if np.Name() == currentNodePoolID || np.AutoScaling() {
 			continue
 		}
 
+		if len(targets) > 0 && targets.SizeFor(np) == np.NodeCount() {
+			continue
+		}
+
 		targetPools = append(targetPools, np)
 	}
 
-	// Set NodePools on instance for resetting/upscaling
-	ktdm.nodePools = targetPools
 	ktdm.autoScaling = &isAutoScalingCluster
 
-	ktdm.log.Log("Resizing all non-autoscaling node groups to 0...")
+	// Stop the cluster-autoscaler from resizing the node pools back up while turned down. Flattened
+	// clusters rely on the autoscaler to shrink the autoscaling node pools, so it is left running.
+	paused := false
+	if !isAutoScalingCluster && len(targetPools) > 0 {
+		err := ktdm.pauseClusterAutoscaler()
+		if err != nil {
+			return err
+		}
+		paused = true
+	}
 
-	// 5. Resize all the non-autoscaling node pools to 0
-	err = ktdm.provider.SetNodePoolSizes(targetPools, 0)
+	if len(targets) > 0 {
+		ktdm.log.Log("Resizing all non-autoscaling node groups to targets: %s...", targets.String())
+	} else {
+		ktdm.log.Log("Resizing all non-autoscaling node groups to 0...")
+	}
+
+	// 5. Resize all the non-autoscaling node pools to their targets, and set the NodePools that were
+	// successfully resized on instance for resetting/upscaling
+	start := time.Now()
+	resized, _, _, err := ktdm.resizeNodePools(targetPools, func(pools []provider.NodePool) provider.NodePoolResults {
+		return ktdm.setNodePoolTargets(pools, targets, remover, removals)
+	})
+	ktdm.report.AddStep(TurndownStepResize, start, err)
+	ktdm.nodePools = resized
+	if len(resized) > 0 {
+		ktdm.recordEvent(v1.EventTypeNormal, NodePoolsResized, "Scaled down node pools: %s", strings.Join(ktdm.ScaledDownNodePools(), ", "))
+	} else if paused {
+		// Nothing is scaled down, so there will be no scale up to resume the autoscaler
+		ktdm.resumeClusterAutoscaler()
+	}
 	if err != nil {
+		ktdm.recordEvent(v1.EventTypeWarning, NodePoolResizeFailed, "Failed to scale down node pools: %s", err.Error())
 		// TODO: Any steps that fail AFTER draining should revert the drain step?
 		return err
 	}
@@ -244,6 +526,231 @@ func (ktdm *KubernetesTurndownManager) ScaleDownCluster() error {
 	return nil
 }
 
+// Drains the node unless it was drained before the current scale down or scale up was interrupted.
+// Returns InterruptedErr if shutting down, otherwise the drain error. Failed drains are recorded as
+// completed, so they are not retried on resume.
+func (ktdm *KubernetesTurndownManager) drainNode(node string) error {
+	if ktdm.hasCompleted(TurndownStepDrain + node) {
+		return nil
+	}
+	if ktdm.isStopping() {
+		return InterruptedErr
+	}
+
+	start := time.Now()
+	draininator := NewDraininator(ktdm.client, node, ktdm.stopCh, ktdm.recorder)
+	err := draininator.Drain()
+	if err == InterruptedErr {
+		return err
+	}
+	ktdm.report.AddStep(TurndownStepDrain+node, start, err)
+	if err != nil {
+		ktdm.log.Err("Failed: %s - Error: %s", node, err.Error())
+		ktdm.recordEvent(v1.EventTypeWarning, NodeDrainFailed, "Failed to drain node %s: %s", node, err.Error())
+	} else {
+		ktdm.report.AddNode(node)
+	}
+	ktdm.completeStep(TurndownStepDrain + node)
+
+	return err
+}
+
+// Scales any cluster-autoscaler to zero, so it does not resize the node pools back up while turned down
+func (ktdm *KubernetesTurndownManager) pauseClusterAutoscaler() error {
+	err := provider.PauseClusterAutoscaler(ktdm.client)
+	if err != nil {
+		ktdm.log.Err("Failed to pause cluster-autoscaler: %s", err.Error())
+		ktdm.recordEvent(v1.EventTypeWarning, AutoscalerPauseFailed, "Failed to pause cluster-autoscaler: %s", err.Error())
+		return err
+	}
+
+	ktdm.recordEvent(v1.EventTypeNormal, AutoscalerPaused, "Paused cluster-autoscaler")
+	return nil
+}
+
+// Restores the replicas of any cluster-autoscaler paused by turndown
+func (ktdm *KubernetesTurndownManager) resumeClusterAutoscaler() error {
+	resumed, err := provider.ResumeClusterAutoscaler(ktdm.client)
+	if err != nil {
+		ktdm.log.Err("Failed to resume cluster-autoscaler: %s", err.Error())
+		ktdm.recordEvent(v1.EventTypeWarning, AutoscalerResumeFailed, "Failed to resume cluster-autoscaler: %s", err.Error())
+		return err
+	}
+
+	if resumed {
+		ktdm.recordEvent(v1.EventTypeNormal, AutoscalerResumed, "Resumed cluster-autoscaler")
+	}
+	return nil
+}
+
+// Resizes the node pools to their target sizes. Node pools with drained nodes to remove have those nodes
+// removed, and the remaining node pools sharing a target size are resized together.
+func (ktdm *KubernetesTurndownManager) setNodePoolTargets(nodePools []provider.NodePool, targets NodePoolTargets, remover provider.NodeRemover, removals map[string][]v1.Node) provider.NodePoolResults {
+	results := provider.NodePoolResults{}
+
+	sizes := []int32{}
+	bySize := make(map[int32][]provider.NodePool)
+	for _, np := range nodePools {
+		if nodes := removals[np.Name()]; len(nodes) > 0 && remover != nil {
+			results = append(results, remover.RemoveNodes(np, nodes))
+			continue
+		}
+
+		size := targets.SizeFor(np)
+		if _, ok := bySize[size]; !ok {
+			sizes = append(sizes, size)
+		}
+		bySize[size] = append(bySize[size], np)
+	}
+
+	for _, size := range sizes {
+		results = append(results, ktdm.provider.SetNodePoolSizes(bySize[size], size)...)
+	}
+
+	return results
+}
+
+// Selects the nodes to remove from a node pool being scaled down to the keep size. If the provider sizes
+// the node pool per zone, the keep size applies to each zone, so the nodes are selected per zone.
+func (ktdm *KubernetesTurndownManager) nodesToRemoveFor(np provider.NodePool, nodes []v1.Node, keep int) []v1.Node {
+	zonal, ok := ktdm.provider.(provider.ZonalNodeRemover)
+	if !ok {
+		return ktdm.nodesToRemove(nodes, keep)
+	}
+
+	zones := []string{}
+	byZone := make(map[string][]v1.Node)
+	for _, n := range nodes {
+		zone := zonal.NodePoolZoneFor(np, &n)
+		if _, ok := byZone[zone]; !ok {
+			zones = append(zones, zone)
+		}
+		byZone[zone] = append(byZone[zone], n)
+	}
+
+	var toRemove []v1.Node
+	for _, zone := range zones {
+		toRemove = append(toRemove, ktdm.nodesToRemove(byZone[zone], keep)...)
+	}
+
+	return toRemove
+}
+
+// Selects the nodes to remove from a node pool being scaled down to the keep size. Nodes cordoned by an
+// interrupted scale down are removed first, so the same nodes are selected on resume, followed by the
+// nodes running the fewest pods, so the least amount of work is evicted.
+func (ktdm *KubernetesTurndownManager) nodesToRemove(nodes []v1.Node, keep int) []v1.Node {
+	if len(nodes) <= keep {
+		return nil
+	}
+
+	podCounts := make(map[string]int)
+	for _, n := range nodes {
+		pods, err := ktdm.client.CoreV1().Pods(metav1.NamespaceAll).List(metav1.ListOptions{
+			FieldSelector: podNodeSelector(n.Name),
+		})
+		if err != nil {
+			ktdm.log.Warn("Failed to list pods for node: %s - %s", n.Name, err.Error())
+			continue
+		}
+
+		podCounts[n.Name] = len(pods.Items)
+	}
+
+	sorted := make([]v1.Node, len(nodes))
+	copy(sorted, nodes)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		_, iCordoned := sorted[i].Annotations[KubecostTurnDownCordoned]
+		_, jCordoned := sorted[j].Annotations[KubecostTurnDownCordoned]
+		if iCordoned != jCordoned {
+			return iCordoned
+		}
+		if podCounts[sorted[i].Name] != podCounts[sorted[j].Name] {
+			return podCounts[sorted[i].Name] < podCounts[sorted[j].Name]
+		}
+		return sorted[i].Name < sorted[j].Name
+	})
+
+	return sorted[:len(sorted)-keep]
+}
+
+// Uncordons any nodes which were cordoned by turndown and were not removed by scaling down.
+func (ktdm *KubernetesTurndownManager) uncordonNodes() error {
+	nodes, err := ktdm.client.CoreV1().Nodes().List(metav1.ListOptions{})
+	if err != nil {
+		return err
+	}
+
+	for _, n := range nodes.Items {
+		if _, ok := n.Annotations[KubecostTurnDownCordoned]; !ok {
+			continue
+		}
+
+		err := NewDraininator(ktdm.client, n.Name, ktdm.stopCh, ktdm.recorder).UncordonNode()
+		if err != nil {
+			ktdm.log.Err("Failed to uncordon node: %s - %s", n.Name, err.Error())
+		}
+	}
+
+	return nil
+}
+
+// Runs the resize operation on the node pools, retrying only the node pools which failed. Returns the
+// node pools that were successfully resized, the node pools that still failed after all retries, the
+// node pools that were skipped, and an error describing the failed and skipped node pools. Returns
+// InterruptedErr if shutting down while waiting to retry.
+func (ktdm *KubernetesTurndownManager) resizeNodePools(nodePools []provider.NodePool, resize func([]provider.NodePool) provider.NodePoolResults) (resized []provider.NodePool, failed []provider.NodePool, skipped []provider.NodePool, err error) {
+	resized = []provider.NodePool{}
+	skipped = []provider.NodePool{}
+	failed = nodePools
+
+	var skippedErrs []string
+	for attempt := 1; attempt <= NodePoolResizeRetries && len(failed) > 0; attempt++ {
+		if attempt > 1 {
+			ktdm.log.Log("Retrying resize for %d failed node pool(s) in %s...", len(failed), NodePoolResizeRetryDelay)
+
+			select {
+			case <-ktdm.stopCh:
+				return resized, failed, skipped, InterruptedErr
+			case <-time.After(NodePoolResizeRetryDelay):
+			}
+		}
+
+		results := resize(failed)
+		for _, r := range results {
+			if r.Outcome == provider.NodePoolFailed {
+				ktdm.log.Err("Failed to resize NodePool: %s from %d to %d - Error: %s", r.NodePool.Name(), r.PreviousSize, r.RequestedSize, r.Error.Error())
+			} else if r.Outcome == provider.NodePoolSkipped {
+				reason := "unknown reason"
+				if r.Error != nil {
+					reason = r.Error.Error()
+				}
+				ktdm.log.Warn("Skipped resize of NodePool: %s - %s", r.NodePool.Name(), reason)
+				skippedErrs = append(skippedErrs, fmt.Sprintf("%s: %s", r.NodePool.Name(), reason))
+			}
+		}
+
+		resized = append(resized, results.Resized()...)
+		skipped = append(skipped, results.Skipped()...)
+		failed = results.Failed()
+		err = results.Err()
+	}
+
+	// Skipped node pools are not retried, but are still reported, as they were not resized
+	if len(skipped) > 0 {
+		ktdm.recordEvent(v1.EventTypeWarning, NodePoolsSkipped, "Skipped resize of node pools: %s", strings.Join(nodePoolNames(skipped), ", "))
+
+		skippedErr := fmt.Sprintf("Skipped resize of %d node pool(s): [%s]", len(skippedErrs), strings.Join(skippedErrs, ", "))
+		if err != nil {
+			err = fmt.Errorf("%s, %s", err.Error(), skippedErr)
+		} else {
+			err = fmt.Errorf("%s", skippedErr)
+		}
+	}
+
+	return resized, failed, skipped, err
+}
+
 func (ktdm *KubernetesTurndownManager) loadNodePools() error {
 	pools, err := ktdm.provider.GetNodePools()
 	if err != nil {
@@ -276,44 +783,118 @@ func (ktdm *KubernetesTurndownManager) ScaleUpCluster() error {
 			ktdm.log.Err("Failed to load NodeGroups: %s", err.Error())
 
 			// Check for autoscaling expansion
-			flattener := NewFlattener(ktdm.client, KubecostFlattenerOmit)
+			flattener := ktdm.newFlattener()
 
 			isAutoscaling := flattener.IsClusterFlattened()
 			ktdm.autoScaling = &isAutoscaling
 		}
 	}
 
+	// Night pools are swapped back for the day pools rather than expanding the cluster
+	if len(ktdm.nodePools) > 0 && !provider.IsWorkloadOnly(ktdm.provider) {
+		nightSwap, err := LoadNightSwapConfig(ktdm.client)
+		if err != nil {
+			ktdm.log.Err("Failed to load night swap configuration: %s", err.Error())
+			return err
+		}
+		if nightSwap != nil {
+			return ktdm.swapToDayPools(nightSwap)
+		}
+	}
+
+	// Workload-only turndown always flattens, so expand if the state was lost on restart
+	if ktdm.autoScaling == nil && provider.IsWorkloadOnly(ktdm.provider) {
+		isFlattened := ktdm.newFlattener().IsClusterFlattened()
+		ktdm.autoScaling = &isFlattened
+	}
+
+	// Karpenter provisioning disabled by turndown means the cluster was flattened
+	karpenter, hasKarpenter := NewKarpenter(ktdm.client, ktdm.dynamic)
+	if hasKarpenter && (ktdm.autoScaling == nil || !*ktdm.autoScaling) && karpenter.IsProvisioningDisabled() {
+		isAutoscaling := true
+		ktdm.autoScaling = &isAutoscaling
+	}
+
 	// At this point, if our nodepool count is 0, it just means we have only
 	// autoscaling node pools. Only reset node pool counts if we have non-autoscaling pools.
-	if len(ktdm.nodePools) > 0 {
+	if ktdm.hasCompleted(TurndownStepResize) {
+		ktdm.log.Log("NodeGroups were reset before interruption. Skipping.")
+	} else if len(ktdm.nodePools) > 0 {
 		ktdm.log.Log("Resetting all NodeGroup sizes to pre-turndown capacity...")
 
-		// 2. Set NodePool sizes back to what they were previously
-		err := ktdm.provider.ResetNodePoolSizes(ktdm.nodePools)
+		// 2. Set NodePool sizes back to what they were previously. Any node pools which failed
+		// to reset remain on the instance, so a subsequent scale up only retries those.
+		start := time.Now()
+		resized, failed, _, err := ktdm.resizeNodePools(ktdm.nodePools, ktdm.provider.ResetNodePoolSizes)
+		ktdm.report.AddStep(TurndownStepResize, start, err)
+		if len(resized) > 0 {
+			ktdm.recordEvent(v1.EventTypeNormal, NodePoolsResized, "Reset node pools to pre-turndown sizes: %s", strings.Join(nodePoolNames(resized), ", "))
+		}
+		if err != nil {
+			ktdm.recordEvent(v1.EventTypeWarning, NodePoolResizeFailed, "Failed to reset node pools: %s", err.Error())
+			ktdm.nodePools = failed
+			return err
+		}
+		ktdm.completeStep(TurndownStepResize)
+	}
+
+	// The cluster-autoscaler is resumed once the node pools are restored, so it does not act on the
+	// resize. It is resumed even if no node pools were recorded, as the paused replicas are stored on
+	// the deployment.
+	err := ktdm.resumeClusterAutoscaler()
+	if err != nil {
+		return err
+	}
+
+	if ktdm.isStopping() {
+		return InterruptedErr
+	}
+
+	// Restore Karpenter limits so pending pods can be provisioned once expanded
+	if hasKarpenter {
+		ktdm.log.Log("Restoring Karpenter Provisioning...")
+
+		err := karpenter.RestoreProvisioning()
 		if err != nil {
 			return err
 		}
 	}
 
 	// 3. Expand Autoscaling Nodes or Resume Jobs
-	flattener := NewFlattener(ktdm.client, KubecostFlattenerOmit)
-	if ktdm.autoScaling != nil && *ktdm.autoScaling {
+	flattener := ktdm.newFlattener()
+	if ktdm.hasCompleted(TurndownStepRestore) {
+		ktdm.log.Log("Cluster was expanded before interruption. Skipping.")
+	} else if ktdm.autoScaling != nil && *ktdm.autoScaling {
 		ktdm.log.Log("Expanding Cluster...")
 
+		start := time.Now()
 		err := flattener.Expand()
+		ktdm.report.AddStep(TurndownStepRestore, start, err)
 		if err != nil {
+			ktdm.recordEvent(v1.EventTypeWarning, ExpandFailed, "Failed to expand cluster: %s", err.Error())
 			return err
 		}
+		ktdm.recordEvent(v1.EventTypeNormal, WorkloadsExpanded, "Expanded deployments and daemonsets, and resumed cron jobs")
 	} else {
 		ktdm.log.Log("Resuming Jobs...")
 
+		start := time.Now()
 		err := flattener.ResumeJobs()
+		ktdm.report.AddStep(TurndownStepRestore, start, err)
 		if err != nil {
+			ktdm.recordEvent(v1.EventTypeWarning, ExpandFailed, "Failed to resume cron jobs: %s", err.Error())
 			return err
 		}
+		ktdm.recordEvent(v1.EventTypeNormal, JobsResumed, "Resumed cron jobs")
+	}
+	ktdm.completeStep(TurndownStepRestore)
+
+	// Nodes in node pools which were partially scaled down remain cordoned, so uncordon them
+	err = ktdm.uncordonNodes()
+	if err != nil {
+		ktdm.log.Err("Failed to uncordon nodes: %s", err.Error())
 	}
 
-	// No need to uncordone nodes here because they were complete removed and now added back
 	// Reset node pools on instance
 	ktdm.nodePools = nil
 	ktdm.autoScaling = nil
@@ -333,17 +914,9 @@ func (ktdm *KubernetesTurndownManager) ResetTurndownEnvironment() error {
 		return err
 	}
 
-	// Locate turndown namespace -- default to turndown
-	ns := os.Getenv("TURNDOWN_NAMESPACE")
-	if ns == "" {
-		ns = "turndown"
-	}
+	ns := turndownNamespace()
 
-	// Locate deployment name -- default to cluster-turndown
-	deploymentName := os.Getenv("TURNDOWN_DEPLOYMENT")
-	if deploymentName == "" {
-		deploymentName = "cluster-turndown"
-	}
+	deploymentName := turndownDeployment()
 
 	ktdm.log.Log("Reversing Tolerations and Node Selector on turndown deployment...")
 
@@ -365,3 +938,23 @@ func (ktdm *KubernetesTurndownManager) ResetTurndownEnvironment() error {
 	}
 	return nil
 }
+
+// Locate turndown namespace -- default to turndown
+func turndownNamespace() string {
+	ns := os.Getenv("TURNDOWN_NAMESPACE")
+	if ns == "" {
+		ns = "turndown"
+	}
+
+	return ns
+}
+
+// Locate deployment name -- default to cluster-turndown
+func turndownDeployment() string {
+	name := os.Getenv("TURNDOWN_DEPLOYMENT")
+	if name == "" {
+		name = "cluster-turndown"
+	}
+
+	return name
+}
@@ -0,0 +1,84 @@
+package turndown
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/kubecost/cluster-turndown/pkg/logging"
+	"github.com/kubecost/cluster-turndown/pkg/turndown/provider"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	kubernetesfake "k8s.io/client-go/kubernetes/fake"
+)
+
+// ComputeProvider which sizes node pools per zone, using a fixed zone for each node. Other provider
+// methods are not implemented.
+type testZonalProvider struct {
+	provider.ComputeProvider
+	zones map[string]string
+}
+
+func (p *testZonalProvider) RemoveNodes(np provider.NodePool, nodes []v1.Node) *provider.NodePoolResult {
+	return nil
+}
+
+func (p *testZonalProvider) NodePoolZoneFor(np provider.NodePool, node *v1.Node) string {
+	return p.zones[node.Name]
+}
+
+// Creates 3 nodes in each of 3 zones, returning the nodes and the zone of each node
+func newTestZonalNodes() ([]v1.Node, map[string]string) {
+	nodes := []v1.Node{}
+	zones := make(map[string]string)
+	for _, zone := range []string{"us-central1-a", "us-central1-b", "us-central1-c"} {
+		for i := 1; i <= 3; i++ {
+			name := fmt.Sprintf("node-%s-%d", zone, i)
+			nodes = append(nodes, v1.Node{ObjectMeta: metav1.ObjectMeta{Name: name}})
+			zones[name] = zone
+		}
+	}
+
+	return nodes, zones
+}
+
+func newTestTurndownManager(p provider.ComputeProvider) *KubernetesTurndownManager {
+	return &KubernetesTurndownManager{
+		client:   kubernetesfake.NewSimpleClientset(),
+		provider: p,
+		log:      logging.NamedLogger("TurndownManager"),
+	}
+}
+
+func TestNodesToRemoveKeepsTargetInEachZone(t *testing.T) {
+	nodes, zones := newTestZonalNodes()
+	ktdm := newTestTurndownManager(&testZonalProvider{zones: zones})
+
+	toRemove := ktdm.nodesToRemoveFor(nil, nodes, 2)
+	if len(toRemove) != 3 {
+		t.Fatalf("Expected 3 nodes to remove, got %d", len(toRemove))
+	}
+
+	removed := make(map[string]int)
+	for _, n := range toRemove {
+		removed[zones[n.Name]]++
+	}
+	for zone, count := range removed {
+		if count != 1 {
+			t.Errorf("Expected 1 node removed from zone: %s, got %d", zone, count)
+		}
+	}
+	if len(removed) != 3 {
+		t.Errorf("Expected nodes removed from 3 zones, got %v", removed)
+	}
+}
+
+func TestNodesToRemoveKeepsTargetInTotal(t *testing.T) {
+	nodes, _ := newTestZonalNodes()
+
+	// Providers which do not size node pools per zone keep the target across all zones
+	ktdm := newTestTurndownManager(&testZonalProvider{})
+	if toRemove := ktdm.nodesToRemoveFor(nil, nodes, 2); len(toRemove) != 7 {
+		t.Errorf("Expected 7 nodes to remove, got %d", len(toRemove))
+	}
+}
@@ -26,9 +26,10 @@ type DataEnvelope struct {
 
 // ScheduleTurndownRequest is the POST encoding used to
 type ScheduleTurndownRequest struct {
-	Start  time.Time `json:"start"`
-	End    time.Time `json:"end"`
-	Repeat string    `json:"repeat,omitempty"`
+	Start           time.Time                 `json:"start"`
+	End             time.Time                 `json:"end"`
+	Repeat          string                    `json:"repeat,omitempty"`
+	NodePoolTargets []v1alpha1.NodePoolTarget `json:"nodePoolTargets,omitempty"`
 }
 
 type TurndownEndpoints struct {
@@ -57,7 +58,6 @@ func NewTurndownEndpoints(
 
 func (te *TurndownEndpoints) HandleStartSchedule(w http.ResponseWriter, r *http.Request) {
 	w.Header().Set("Content-Type", "application/json")
-	w.Header().Set("Access-Control-Allow-Origin", "*")
 
 	if r.Method == http.MethodGet {
 		schedule := te.scheduler.GetSchedule()
@@ -103,9 +103,10 @@ func (te *TurndownEndpoints) HandleStartSchedule(w http.ResponseWriter, r *http.
 				},
 			},
 			Spec: v1alpha1.TurndownScheduleSpec{
-				Start:  v1.NewTime(request.Start),
-				End:    v1.NewTime(request.End),
-				Repeat: request.Repeat,
+				Start:           v1.NewTime(request.Start),
+				End:             v1.NewTime(request.End),
+				Repeat:          request.Repeat,
+				NodePoolTargets: request.NodePoolTargets,
 			},
 		})
 		if err != nil {
@@ -143,7 +144,6 @@ func (te *TurndownEndpoints) HandleStartSchedule(w http.ResponseWriter, r *http.
 
 func (te *TurndownEndpoints) HandleCancelSchedule(w http.ResponseWriter, r *http.Request) {
 	w.Header().Set("Content-Type", "application/json")
-	w.Header().Set("Access-Control-Allow-Origin", "*")
 
 	scheduleList, err := te.client.KubecostV1alpha1().TurndownSchedules().List(v1.ListOptions{})
 	if err != nil {
@@ -170,29 +170,203 @@ func (te *TurndownEndpoints) HandleCancelSchedule(w http.ResponseWriter, r *http
 	w.Write(wrapData("", nil))
 }
 
-func (te *TurndownEndpoints) HandleInitEnvironment(w http.ResponseWriter, r *http.Request) {
+func (te *TurndownEndpoints) HandleSnooze(w http.ResponseWriter, r *http.Request) {
 	w.Header().Set("Content-Type", "application/json")
-	w.Header().Set("Access-Control-Allow-Origin", "*")
 
-	isOnNode, err := te.turndown.IsRunningOnTurndownNode()
-	if nil != err {
+	if r.Method != http.MethodPost {
+		resp, _ := json.Marshal(&DataEnvelope{
+			Code:   http.StatusNotFound,
+			Status: "error",
+			Data:   fmt.Sprintf("Not Found for method type: %s", r.Method),
+		})
+		w.Write(resp)
+		return
+	}
+
+	data, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		w.Write(wrapData(nil, err))
+		return
+	}
+
+	var request SnoozeRequest
+	err = json.Unmarshal(data, &request)
+	if err != nil {
+		w.Write(wrapData(nil, err))
+		return
+	}
+
+	schedule, err := te.snooze(request, r)
+	if err != nil {
+		w.Write(wrapData(nil, err))
+		return
+	}
+
+	w.Write(wrapData(schedule, nil))
+}
+
+// Postpones the next scale down using the snooze policy of the scheduled TurndownSchedule resource
+func (te *TurndownEndpoints) snooze(request SnoozeRequest, r *http.Request) (*Schedule, error) {
+	duration, err := time.ParseDuration(request.Duration)
+	if err != nil {
+		return nil, &RequestError{err}
+	}
+
+	// Authenticated users are always recorded as the snoozer
+	if user := UserFromRequest(r); user != "" {
+		request.SnoozedBy = user
+	} else if request.SnoozedBy == "" {
+		request.SnoozedBy = r.RemoteAddr
+	}
+
+	scheduleList, err := te.client.KubecostV1alpha1().TurndownSchedules().List(v1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	var spec *v1alpha1.TurndownScheduleSpec
+	for _, s := range scheduleList.Items {
+		if s.Status.State == ScheduleStateSuccess {
+			spec = &s.Spec
+			break
+		}
+	}
+
+	return te.scheduler.Snooze(duration, maxSnoozeFor(spec), request.SnoozedBy, request.Reason)
+}
+
+func (te *TurndownEndpoints) HandleScaleDown(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	if r.Method != http.MethodPost {
+		resp, _ := json.Marshal(&DataEnvelope{
+			Code:   http.StatusNotFound,
+			Status: "error",
+			Data:   fmt.Sprintf("Not Found for method type: %s", r.Method),
+		})
+		w.Write(resp)
+		return
+	}
+
+	var request ScaleDownRequest
+	data, err := ioutil.ReadAll(r.Body)
+	if err != nil {
 		w.Write(wrapData(nil, err))
 		return
 	}
 
-	if !isOnNode {
-		err := te.turndown.PrepareTurndownEnvironment()
+	if len(data) > 0 {
+		err = json.Unmarshal(data, &request)
 		if err != nil {
 			w.Write(wrapData(nil, err))
 			return
 		}
-	} else {
-		klog.Infof("Already running on correct turndown node. No need to setup environment")
+	}
+
+	schedule, _, err := te.scaleDown(request)
+	if err != nil {
+		w.Write(wrapData(nil, err))
+		return
+	}
+
+	w.Write(wrapData(schedule, nil))
+}
+
+// Scales down the existing schedule until its next scale up, or creates a one-shot schedule resource so
+// the scale down is persisted. Returns whether a new schedule was created.
+func (te *TurndownEndpoints) scaleDown(request ScaleDownRequest) (*Schedule, bool, error) {
+	if te.scheduler.GetSchedule() != nil {
+		err := te.scheduler.ScaleDownNow()
+		if err != nil {
+			return nil, false, err
+		}
+
+		return te.scheduler.GetSchedule(), false, nil
+	}
+
+	_, err := te.client.KubecostV1alpha1().TurndownSchedules().Create(&v1alpha1.TurndownSchedule{
+		ObjectMeta: v1.ObjectMeta{
+			GenerateName: "on-demand-turndown-",
+			Finalizers: []string{
+				TurndownScheduleFinalizer,
+			},
+		},
+		Spec: v1alpha1.TurndownScheduleSpec{
+			Repeat:          TurndownJobRepeatNone,
+			Action:          TurndownJobTypeScaleDown,
+			NodePoolTargets: request.NodePoolTargets,
+		},
+	})
+	if err != nil {
+		return nil, false, err
+	}
+
+	// Poll scheduler until the resource controller has propagated the schedule
+	var schedule *Schedule = nil
+	err = wait.PollImmediate(time.Second*1, time.Second*30, func() (bool, error) {
+		schedule = te.scheduler.GetSchedule()
+		if schedule != nil {
+			return true, nil
+		}
+
+		return false, nil
+	})
+	if err != nil {
+		return nil, false, err
+	}
+
+	return schedule, true, nil
+}
+
+func (te *TurndownEndpoints) HandleScaleUp(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	if r.Method != http.MethodPost {
+		resp, _ := json.Marshal(&DataEnvelope{
+			Code:   http.StatusNotFound,
+			Status: "error",
+			Data:   fmt.Sprintf("Not Found for method type: %s", r.Method),
+		})
+		w.Write(resp)
+		return
+	}
+
+	err := te.scheduler.ScaleUpNow()
+	if err != nil {
+		w.Write(wrapData(nil, err))
+		return
+	}
+
+	w.Write(wrapData(te.scheduler.GetSchedule(), nil))
+}
+
+func (te *TurndownEndpoints) HandleInitEnvironment(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	err := te.prepareEnvironment()
+	if err != nil {
+		w.Write(wrapData(nil, err))
+		return
 	}
 
 	w.Write(wrapData("", nil))
 }
 
+// Prepares the turndown environment unless already running on the turndown node
+func (te *TurndownEndpoints) prepareEnvironment() error {
+	isOnNode, err := te.turndown.IsRunningOnTurndownNode()
+	if nil != err {
+		return err
+	}
+
+	if isOnNode {
+		klog.Infof("Already running on correct turndown node. No need to setup environment")
+		return nil
+	}
+
+	return te.turndown.PrepareTurndownEnvironment()
+}
+
 func wrapData(data interface{}, err error) []byte {
 	var resp []byte
 
@@ -0,0 +1,319 @@
+package turndown
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/kubecost/cluster-turndown/pkg/apis/turndownschedule/v1alpha1"
+	clientset "github.com/kubecost/cluster-turndown/pkg/generated/clientset/versioned"
+	"github.com/kubecost/cluster-turndown/pkg/logging"
+
+	corev1 "k8s.io/api/core/v1"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// Labels applied to each TurndownRun, so the runs of a schedule can be listed
+	TurndownRunScheduleLabel = "kubecost.kubernetes.io/turndown-schedule"
+	TurndownRunTypeLabel     = "kubecost.kubernetes.io/turndown-run-type"
+
+	// Phase of a TurndownRun which has not yet completed. Completed runs use the run outcomes.
+	RunRunning = "Running"
+
+	// Default retention of completed TurndownRun resources
+	DefaultRunRetention    = 30 * 24 * time.Hour
+	DefaultRunHistoryLimit = 50
+)
+
+// RunReport collects the result of each step of a scale down or scale up, along with the nodes and
+// workloads it touched. All methods are safe to call on a nil report.
+type RunReport struct {
+	lock      *sync.Mutex
+	steps     []v1alpha1.TurndownStepResult
+	nodes     []string
+	workloads []string
+}
+
+// Creates a new empty RunReport
+func NewRunReport() *RunReport {
+	return &RunReport{
+		lock: new(sync.Mutex),
+	}
+}
+
+// Records the result of a step which started at the provided time and has just completed
+func (rr *RunReport) AddStep(step string, start time.Time, err error) {
+	if rr == nil {
+		return
+	}
+
+	result := v1alpha1.TurndownStepResult{
+		Name:    step,
+		Start:   v1.NewTime(start.UTC()),
+		End:     v1.NewTime(time.Now().UTC()),
+		Outcome: RunSucceeded,
+	}
+	if err != nil {
+		result.Outcome = RunFailed
+		result.Error = err.Error()
+	}
+
+	rr.lock.Lock()
+	defer rr.lock.Unlock()
+
+	rr.steps = append(rr.steps, result)
+}
+
+// Records a node drained by the run
+func (rr *RunReport) AddNode(node string) {
+	if rr == nil {
+		return
+	}
+
+	rr.lock.Lock()
+	defer rr.lock.Unlock()
+
+	rr.nodes = append(rr.nodes, node)
+}
+
+// Records a workload updated by the run, ie: deployment/default/api
+func (rr *RunReport) AddWorkload(workload string) {
+	if rr == nil {
+		return
+	}
+
+	rr.lock.Lock()
+	defer rr.lock.Unlock()
+
+	rr.workloads = append(rr.workloads, workload)
+}
+
+// Writes the outcome of the run, along with the collected steps, nodes and workloads to the status
+func (rr *RunReport) WriteStatus(status *v1alpha1.TurndownRunStatus, run *ScheduleRun) {
+	status.Phase = run.Outcome
+	status.Start = v1.NewTime(run.Start)
+	status.End = v1.NewTime(run.End)
+	status.Duration = v1.Duration{Duration: run.End.Sub(run.Start)}
+	status.Pools = run.Pools
+	status.Error = run.Error
+
+	if rr == nil {
+		return
+	}
+
+	rr.lock.Lock()
+	defer rr.lock.Unlock()
+
+	status.Steps = rr.steps
+	status.Nodes = rr.nodes
+	status.Workloads = rr.workloads
+}
+
+// Persistent storage for a record of each scale down and scale up
+type RunStore interface {
+	// Creates the record of a run starting at the provided time, labeled with the schedule if the
+	// reference is set. Returns the name of the record.
+	Create(ref *corev1.ObjectReference, spec v1alpha1.TurndownRunSpec, start time.Time) (string, error)
+
+	// Records the outcome of the run
+	Complete(name string, run *ScheduleRun, report *RunReport) error
+}
+
+// KubernetesRunStore records each run as a TurndownRun resource
+type KubernetesRunStore struct {
+	client clientset.Interface
+}
+
+func NewKubernetesRunStore(client clientset.Interface) RunStore {
+	return &KubernetesRunStore{
+		client: client,
+	}
+}
+
+func (krs *KubernetesRunStore) Create(ref *corev1.ObjectReference, spec v1alpha1.TurndownRunSpec, start time.Time) (string, error) {
+	run := &v1alpha1.TurndownRun{
+		ObjectMeta: v1.ObjectMeta{
+			GenerateName: fmt.Sprintf("turndown-%s-", spec.Type),
+			Labels: map[string]string{
+				TurndownRunTypeLabel: spec.Type,
+			},
+		},
+		Spec: spec,
+	}
+
+	if ref != nil {
+		run.GenerateName = fmt.Sprintf("%s-%s-", ref.Name, spec.Type)
+		// Runs are not owned by the schedule, so they outlive it as a history of its runs until they
+		// are pruned by the TurndownRunPruner
+		run.Labels[TurndownRunScheduleLabel] = ref.Name
+	}
+
+	// The status subresource is ignored on create
+	created, err := krs.client.KubecostV1alpha1().TurndownRuns().Create(run)
+	if err != nil {
+		return "", err
+	}
+
+	created.Status = v1alpha1.TurndownRunStatus{
+		Phase: RunRunning,
+		Start: v1.NewTime(start.UTC().Truncate(time.Second)),
+	}
+
+	_, err = krs.client.KubecostV1alpha1().TurndownRuns().UpdateStatus(created)
+	return created.Name, err
+}
+
+func (krs *KubernetesRunStore) Complete(name string, run *ScheduleRun, report *RunReport) error {
+	tr, err := krs.client.KubecostV1alpha1().TurndownRuns().Get(name, v1.GetOptions{})
+	if err != nil {
+		return err
+	}
+
+	trCopy := tr.DeepCopy()
+	report.WriteStatus(&trCopy.Status, run)
+
+	_, err = krs.client.KubecostV1alpha1().TurndownRuns().UpdateStatus(trCopy)
+	return err
+}
+
+// TurndownRunPruner deletes completed TurndownRun resources which are older than the retention, or
+// exceed the history limit of their schedule. A retention or limit of 0 is ignored. Runs which were
+// still running when a previous pod stopped are marked as interrupted.
+type TurndownRunPruner struct {
+	client    clientset.Interface
+	retention time.Duration
+	limit     int
+	started   time.Time
+	log       logging.NamedLogger
+}
+
+// Creates a new TurndownRunPruner. Runs created before the pruner are considered stale if they are still
+// running, so it must be created before the scheduler starts any runs.
+func NewTurndownRunPruner(client clientset.Interface, retention time.Duration, limit int) *TurndownRunPruner {
+	return &TurndownRunPruner{
+		client:    client,
+		retention: retention,
+		limit:     limit,
+		started:   time.Now(),
+		log:       logging.NamedLogger("TurndownRunPruner"),
+	}
+}
+
+// Closes stale runs, then runs a loop that prunes TurndownRun resources until stopCh is closed
+func (trp *TurndownRunPruner) Run(stopCh <-chan struct{}) {
+	go func() {
+		trp.closeStaleRuns()
+
+		if trp.retention <= 0 && trp.limit <= 0 {
+			return
+		}
+
+		ticker := time.NewTicker(10 * time.Minute)
+		for {
+			select {
+			case <-ticker.C:
+				trp.prune()
+			case <-stopCh:
+				ticker.Stop()
+				return
+			}
+		}
+	}()
+}
+
+// Marks runs which were left running by a previous pod, ie: after a crash or a change of leader, as
+// interrupted, so they are pruned like completed runs.
+func (trp *TurndownRunPruner) closeStaleRuns() {
+	runs, err := trp.client.KubecostV1alpha1().TurndownRuns().List(v1.ListOptions{})
+	if err != nil {
+		trp.log.Err("Failed to list turndown runs: %s", err.Error())
+		return
+	}
+
+	// Creation timestamps are truncated to the second
+	started := trp.started.Truncate(time.Second)
+
+	now := time.Now().UTC()
+	for _, run := range runs.Items {
+		if !isRunOpen(&run) || !run.CreationTimestamp.Time.Before(started) {
+			continue
+		}
+
+		runCopy := run.DeepCopy()
+		if runCopy.Status.Start.IsZero() {
+			runCopy.Status.Start = runCopy.CreationTimestamp
+		}
+		runCopy.Status.Phase = RunInterrupted
+		runCopy.Status.End = v1.NewTime(now.Truncate(time.Second))
+		runCopy.Status.Duration = v1.Duration{Duration: runCopy.Status.End.Sub(runCopy.Status.Start.Time)}
+		runCopy.Status.Error = "The run did not complete before turndown restarted"
+
+		_, err := trp.client.KubecostV1alpha1().TurndownRuns().UpdateStatus(runCopy)
+		if err != nil {
+			trp.log.Err("Failed to close stale turndown run: %s - %s", run.Name, err.Error())
+			continue
+		}
+
+		trp.log.Log("Marked stale turndown run: %s as interrupted", run.Name)
+	}
+}
+
+func (trp *TurndownRunPruner) prune() {
+	runs, err := trp.client.KubecostV1alpha1().TurndownRuns().List(v1.ListOptions{})
+	if err != nil {
+		trp.log.Err("Failed to list turndown runs: %s", err.Error())
+		return
+	}
+
+	mustBeAfter := time.Now().UTC().Add(-trp.retention)
+
+	// Completed runs grouped by schedule, newest first. Runs which are still open are only pruned once
+	// they started before the retention, in case they could not be closed.
+	bySchedule := make(map[string][]v1alpha1.TurndownRun)
+	for _, run := range runs.Items {
+		if isRunOpen(&run) {
+			start := run.Status.Start.Time
+			if start.IsZero() {
+				start = run.CreationTimestamp.Time
+			}
+
+			if trp.retention > 0 && start.Before(mustBeAfter) {
+				trp.deleteRun(run.Name)
+			}
+			continue
+		}
+
+		schedule := run.Labels[TurndownRunScheduleLabel]
+		bySchedule[schedule] = append(bySchedule[schedule], run)
+	}
+
+	for _, scheduleRuns := range bySchedule {
+		sort.SliceStable(scheduleRuns, func(i, j int) bool {
+			return scheduleRuns[j].CreationTimestamp.Before(&scheduleRuns[i].CreationTimestamp)
+		})
+
+		for i, run := range scheduleRuns {
+			expired := trp.retention > 0 && run.Status.End.Time.Before(mustBeAfter)
+			exceeded := trp.limit > 0 && i >= trp.limit
+			if !expired && !exceeded {
+				continue
+			}
+
+			trp.deleteRun(run.Name)
+		}
+	}
+}
+
+func (trp *TurndownRunPruner) deleteRun(name string) {
+	err := trp.client.KubecostV1alpha1().TurndownRuns().Delete(name, &v1.DeleteOptions{})
+	if err != nil {
+		trp.log.Err("Failed to delete turndown run: %s - %s", name, err.Error())
+	}
+}
+
+// Returns true if the run has not completed
+func isRunOpen(run *v1alpha1.TurndownRun) bool {
+	return run.Status.Phase == RunRunning || run.Status.Phase == ""
+}
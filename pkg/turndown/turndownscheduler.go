@@ -7,14 +7,24 @@ import (
 	"sync"
 	"time"
 
+	"github.com/kubecost/cluster-turndown/pkg/apis/turndownschedule/v1alpha1"
 	"github.com/kubecost/cluster-turndown/pkg/logging"
 
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/tools/record"
 	"k8s.io/klog"
 )
 
 const (
-	TurndownJobType   = "type"
-	TurndownJobRepeat = "repeat"
+	TurndownJobType    = "type"
+	TurndownJobRepeat  = "repeat"
+	TurndownJobTargets = "targets"
+
+	// Triggered jobs run once outside of the schedule, and only flip the current job type
+	TurndownJobTriggered = "triggered"
+
+	// The original time of a postponed job, used to keep the repeat cadence
+	TurndownJobCadence = "cadence"
 
 	TurndownJobTypeScaleDown = "scaledown"
 	TurndownJobTypeScaleUp   = "scaleup"
@@ -23,6 +33,13 @@ const (
 	TurndownJobRepeatNone   = "none"
 	TurndownJobRepeatDaily  = "daily"
 	TurndownJobRepeatWeekly = "weekly"
+
+	// Reasons recorded on the schedule for the current scale down
+	ScaleDownReasonScheduled   = "Scheduled"
+	ScaleDownReasonIdlePrefix  = "Idle: "
+	ScaleDownReasonWakeElapsed = "Wake Window Elapsed"
+	ScaleDownReasonOnDemand    = "On Demand"
+	ScaleUpReasonOnDemand      = "On Demand"
 )
 
 var (
@@ -36,6 +53,22 @@ var (
 	EnvironmentPrepareErr  = errors.New("EnvironmentPrepare")
 	NoSchedulesToCancelErr = errors.New("No Schedules to Cancel")
 	CancelWhileRunningErr  = errors.New("Cannot Cancel Turndown while Running")
+	NoScheduleToTriggerErr = errors.New("No Schedule to Trigger")
+	AlreadyScaledDownErr   = errors.New("Cluster is already Scaled Down")
+	AlreadyScaledUpErr     = errors.New("Cluster is already Scaled Up")
+	NoScheduleToSnoozeErr  = errors.New("No Schedule to Snooze")
+	SnoozeWhileRunningErr  = errors.New("Cannot Snooze Turndown while Running")
+	InterruptedErr         = errors.New("Interrupted")
+	ScheduleExistsErr      = &ConflictError{errors.New("Currently, only a single turndown schedule is allowed.")}
+
+	NoScheduleToRescheduleErr = errors.New("No Schedule to Reschedule")
+	RescheduleWhileRunningErr = errors.New("Cannot Reschedule Turndown while Running")
+	RescheduleOnDemandErr     = errors.New("Cannot Reschedule an On Demand Turndown")
+)
+
+const (
+	// Maximum total postponement of a scale down if the schedule does not set a snooze policy
+	DefaultMaxSnooze = 4 * time.Hour
 )
 
 type TurndownScheduler struct {
@@ -44,18 +77,28 @@ type TurndownScheduler struct {
 	lock      *sync.Mutex
 	manager   TurndownManager
 	store     ScheduleStore
+	runs      RunStore
+	recorder  record.EventRecorder
 	log       logging.NamedLogger
 
 	// FIXME: Hack while supporting only a single scheduled pair
 	lastTypeCompleted string
+
+	// Triggered jobs which run outside of the schedule
+	triggeredID     string
+	wakeScaleDownID string
 }
 
-func NewTurndownScheduler(manager TurndownManager, store ScheduleStore) *TurndownScheduler {
+// Creates a new TurndownScheduler, restoring the schedule from the store. The result of each scale down
+// and scale up is recorded as an event on the TurndownSchedule, and as a run in the run store.
+func NewTurndownScheduler(manager TurndownManager, store ScheduleStore, runs RunStore, recorder record.EventRecorder) *TurndownScheduler {
 	ts := &TurndownScheduler{
 		scheduler: NewSimpleScheduler(),
 		lock:      new(sync.Mutex),
 		manager:   manager,
 		store:     store,
+		runs:      runs,
+		recorder:  recorder,
 		log:       logging.NamedLogger("TurndownScheduler"),
 	}
 
@@ -93,19 +136,38 @@ func (ts *TurndownScheduler) ScheduleTurndownBySchedule(schedule *Schedule) erro
 	upMeta := schedule.ScaleUpMetadata
 	//upRepeat := upMeta[TurndownJobRepeat]
 
+	// On demand schedules do not have a scale up until one is requested
+	onDemand := upTime.IsZero()
+
 	current := schedule.Current
+
+	// A scale down or scale up interrupted by shutdown resumes now from its completed steps rather
+	// than offsetting the schedule
+	resume := schedule.InterruptedJob != "" && schedule.InterruptedJob == current
+	if resume {
+		ts.manager.ResumeSteps(schedule.CompletedSteps)
+	}
+
 	if current == TurndownJobTypeScaleDown {
 		// If we've missed the scale down time, offset by the missed time and apply upTime
 		// both downTime and upTime times
-		if downTime.Before(now) {
+		if resume && downTime.Before(now) {
+			klog.V(3).Infof("Resuming Interrupted Scale Down from: %s", downTime)
+			downTime, downMeta = now, withCadence(downMeta, downTime)
+		} else if downTime.Before(now) {
 			delta := now.Sub(downTime) + (1 * time.Minute)
 			downTime = downTime.Add(delta)
-			upTime = upTime.Add(delta)
+			if !onDemand {
+				upTime = upTime.Add(delta)
+			}
 		}
-	} else {
+	} else if !onDemand {
 		// If we've missed the scale up time, offset by the missed time and apply upTime
 		// both downTime and upTime times
-		if upTime.Before(now) {
+		if resume && upTime.Before(now) {
+			klog.V(3).Infof("Resuming Interrupted Scale Up from: %s", upTime)
+			upTime, upMeta = now, withCadence(upMeta, upTime)
+		} else if upTime.Before(now) {
 			delta := now.Sub(upTime) + (1 * time.Minute)
 			downTime = downTime.Add(delta)
 			upTime = upTime.Add(delta)
@@ -125,13 +187,35 @@ func (ts *TurndownScheduler) ScheduleTurndownBySchedule(schedule *Schedule) erro
 		}
 	}
 
-	_, err = ts.scheduler.ScheduleWithID(schedule.ScaleUpID, upTime, ts.scaleUp, upMeta)
-	if err != nil {
-		if scaleDownID != "" {
-			ts.scheduler.Cancel(scaleDownID)
+	if onDemand {
+		klog.V(3).Infof("On Demand Schedule without a Scale Up. Omitting Scale Up Schedule.")
+	} else {
+		_, err = ts.scheduler.ScheduleWithID(schedule.ScaleUpID, upTime, ts.scaleUp, upMeta)
+		if err != nil {
+			if scaleDownID != "" {
+				ts.scheduler.Cancel(scaleDownID)
+			}
+			ts.store.Clear()
+			return err
 		}
-		ts.store.Clear()
-		return err
+	}
+
+	// A triggered scale down which did not complete (ie: moving to the turndown node) must run again, and
+	// a cluster woken early scales back down once the wake window elapses
+	if current == TurndownJobTypeScaleDown {
+		if resume && !schedule.ScaleDownTime.Before(now) {
+			klog.V(3).Infof("Resuming Interrupted Triggered Scale Down")
+			ts.triggeredID, _ = ts.scheduler.Schedule(now, ts.scaleDown, triggeredScaleDownMetadata(downMeta))
+		} else if !schedule.WakeUntil.IsZero() {
+			klog.V(3).Infof("Resuming Wake Window until: %s", schedule.WakeUntil)
+			ts.wakeScaleDownID, _ = ts.scheduler.Schedule(schedule.WakeUntil, ts.scaleDown, triggeredScaleDownMetadata(downMeta))
+		} else if strings.HasPrefix(schedule.ScaleDownReason, ScaleDownReasonIdlePrefix) {
+			klog.V(3).Infof("Resuming Triggered Scale Down: %s", schedule.ScaleDownReason)
+			ts.triggeredID, _ = ts.scheduler.Schedule(now, ts.scaleDown, triggeredScaleDownMetadata(downMeta))
+		}
+	} else if resume && !onDemand && !schedule.ScaleUpTime.Before(now) {
+		klog.V(3).Infof("Resuming Interrupted Triggered Scale Up")
+		ts.triggeredID, _ = ts.scheduler.Schedule(now, ts.scaleUp, triggeredScaleUpMetadata())
 	}
 
 	ts.schedule = schedule
@@ -160,6 +244,57 @@ func fixupRepeatType(repeatType *string) string {
 
 // Determine whether or not a request scheduled is valid.
 func validateSchedule(from time.Time, to time.Time, repeatType *string) error {
+	// Check To relative to Now
+	now := time.Now()
+	if now.After(from) {
+		return fmt.Errorf("The start time (%s) was set to a time in the past (now=%s).", from, now)
+	}
+
+	return validateScheduleRange(from, to, repeatType)
+}
+
+// Determine whether or not a schedule is valid to replace the current schedule. While the cluster is
+// scaled down, the start time may be in the past, but the end time must not be.
+func validateReschedule(from time.Time, to time.Time, repeatType *string, scaledDown bool) error {
+	if !scaledDown {
+		return validateSchedule(from, to, repeatType)
+	}
+
+	now := time.Now()
+	if now.After(to) {
+		return fmt.Errorf("The end time (%s) was set to a time in the past while the cluster is scaled down (now=%s).", to, now)
+	}
+
+	return validateScheduleRange(from, to, repeatType)
+}
+
+// Projects the start and end times of a repeating schedule to their next occurrence, so a schedule created
+// in the past can still be edited. While the cluster is scaled up, the start time is moved into the future.
+// While scaled down, the end time is moved into the future, and the start time may remain in the past.
+// Schedules which do not repeat are returned as is.
+func projectSchedule(from time.Time, to time.Time, repeatType string, scaledDown bool) (time.Time, time.Time) {
+	repeatDuration := repeatDurations[fixupRepeatType(&repeatType)]
+	if repeatDuration <= 0 {
+		return from, to
+	}
+
+	next := from
+	if scaledDown {
+		next = to
+	}
+
+	now := time.Now()
+	if !now.After(next) {
+		return from, to
+	}
+
+	repeats := now.Sub(next)/repeatDuration + 1
+	offset := repeats * repeatDuration
+	return from.Add(offset), to.Add(offset)
+}
+
+// Determine whether or not the start and end times and repeat type are valid, regardless of the current time.
+func validateScheduleRange(from time.Time, to time.Time, repeatType *string) error {
 	// Check From -> To Range
 	delta := to.Sub(from)
 	if delta < 0 {
@@ -171,12 +306,6 @@ func validateSchedule(from time.Time, to time.Time, repeatType *string) error {
 		return fmt.Errorf("The start time (%s) and end time (%s) must be at least 20 mins apart.", from, to)
 	}
 
-	// Check To relative to Now
-	now := time.Now()
-	if now.After(from) {
-		return fmt.Errorf("The start time (%s) was set to a time in the past (now=%s).", from, now)
-	}
-
 	// Check Repetition Type
 	repeatDuration, ok := repeatDurations[fixupRepeatType(repeatType)]
 	if !ok {
@@ -191,20 +320,27 @@ func validateSchedule(from time.Time, to time.Time, repeatType *string) error {
 	return nil
 }
 
-// Schedules Turndown for the current kubernetes cluster
-func (ts *TurndownScheduler) ScheduleTurndown(from time.Time, to time.Time, repeatType string) (*Schedule, error) {
+// Schedules Turndown for the current kubernetes cluster. Node pools with a target are scaled down to
+// the target size rather than 0.
+func (ts *TurndownScheduler) ScheduleTurndown(from time.Time, to time.Time, repeatType string, targets NodePoolTargets) (*Schedule, error) {
 	ts.lock.Lock()
 	defer ts.lock.Unlock()
 
 	// Already a turndown schedule
 	if ts.schedule != nil {
 		ts.log.Err("Failed to scheduled turndown. Schedule already exists.")
-		return nil, fmt.Errorf("Currently, only a single turndown schedule is allowed.")
+		return nil, ScheduleExistsErr
 	}
 
 	err := validateSchedule(from, to, &repeatType)
 	if err != nil {
 		ts.log.Err("Failed to validate schedule: %s", err.Error())
+		return nil, &RequestError{err}
+	}
+
+	err = targets.Validate()
+	if err != nil {
+		ts.log.Err("Failed to validate node pool targets: %s", err.Error())
 		return nil, err
 	}
 
@@ -213,6 +349,9 @@ func (ts *TurndownScheduler) ScheduleTurndown(from time.Time, to time.Time, repe
 		TurndownJobType:   TurndownJobTypeScaleDown,
 		TurndownJobRepeat: repeatType,
 	}
+	if len(targets) > 0 {
+		scaleDownMeta[TurndownJobTargets] = targets.String()
+	}
 	scaleDownID, err := ts.scheduler.Schedule(from, ts.scaleDown, scaleDownMeta)
 	if err != nil {
 		return nil, err
@@ -245,6 +384,188 @@ func (ts *TurndownScheduler) ScheduleTurndown(from time.Time, to time.Time, repe
 	return &toReturn, nil
 }
 
+// ScheduleScaleDownNow creates a one-shot schedule which scales the cluster down immediately. There is no
+// scheduled scale up, so the cluster stays down until ScaleUpNow is called or the schedule is cancelled.
+func (ts *TurndownScheduler) ScheduleScaleDownNow(targets NodePoolTargets) (*Schedule, error) {
+	ts.lock.Lock()
+	defer ts.lock.Unlock()
+
+	if ts.schedule != nil {
+		ts.log.Err("Failed to scale down. Schedule already exists.")
+		return nil, ScheduleExistsErr
+	}
+
+	err := targets.Validate()
+	if err != nil {
+		ts.log.Err("Failed to validate node pool targets: %s", err.Error())
+		return nil, err
+	}
+
+	now := time.Now()
+	scaleDownMeta := map[string]string{
+		TurndownJobType:   TurndownJobTypeScaleDown,
+		TurndownJobRepeat: TurndownJobRepeatNone,
+	}
+	if len(targets) > 0 {
+		scaleDownMeta[TurndownJobTargets] = targets.String()
+	}
+	scaleDownID, err := ts.scheduler.Schedule(now, ts.scaleDown, scaleDownMeta)
+	if err != nil {
+		return nil, err
+	}
+
+	ts.schedule = &Schedule{
+		Current:           TurndownJobTypeScaleDown,
+		ScaleDownID:       scaleDownID,
+		ScaleDownTime:     now,
+		ScaleDownMetadata: scaleDownMeta,
+		ScaleDownReason:   ScaleDownReasonOnDemand,
+	}
+
+	ts.store.Create(ts.schedule)
+
+	ts.log.Log("On Demand Scale Down Created: %+v", ts.schedule)
+
+	toReturn := *ts.schedule
+	return &toReturn, nil
+}
+
+// Reschedule replaces the scale down and scale up of the current schedule, ie: when the TurndownSchedule
+// spec is edited. The jobs are replaced while holding the lock, so no job runs in between. If the cluster
+// is currently scaled down, the start time may be in the past. The cluster then scales up at the new end
+// time, and scales down again at the next repeat of the start time. The start and end times of a repeating
+// schedule are first projected to their next occurrence.
+func (ts *TurndownScheduler) Reschedule(from time.Time, to time.Time, repeatType string, targets NodePoolTargets) (*Schedule, error) {
+	ts.lock.Lock()
+	defer ts.lock.Unlock()
+
+	if ts.schedule == nil {
+		return nil, NoScheduleToRescheduleErr
+	}
+
+	if ts.schedule.ScaleUpTime.IsZero() {
+		return nil, RescheduleOnDemandErr
+	}
+
+	downID := ts.schedule.ScaleDownID
+	upID := ts.schedule.ScaleUpID
+	if ts.scheduler.IsRunning(downID) || ts.scheduler.IsRunning(upID) || ts.isTriggerPending() {
+		return nil, RescheduleWhileRunningErr
+	}
+
+	scaledDown := ts.schedule.Current == TurndownJobTypeScaleUp
+	from, to = projectSchedule(from, to, repeatType, scaledDown)
+
+	err := validateReschedule(from, to, &repeatType, scaledDown)
+	if err != nil {
+		ts.log.Err("Failed to validate schedule: %s", err.Error())
+		return nil, &RequestError{err}
+	}
+
+	err = targets.Validate()
+	if err != nil {
+		ts.log.Err("Failed to validate node pool targets: %s", err.Error())
+		return nil, err
+	}
+
+	scaleDownMeta := map[string]string{
+		TurndownJobType:   TurndownJobTypeScaleDown,
+		TurndownJobRepeat: repeatType,
+	}
+	if len(targets) > 0 {
+		scaleDownMeta[TurndownJobTargets] = targets.String()
+	}
+	scaleUpMeta := map[string]string{
+		TurndownJobType:   TurndownJobTypeScaleUp,
+		TurndownJobRepeat: repeatType,
+	}
+
+	// While scaled down, the scale down for the current window already ran
+	downTime := from
+	if scaledDown {
+		downTime = from.Add(repeatDurations[repeatType])
+	}
+
+	ts.scheduler.Cancel(downID)
+	ts.scheduler.Cancel(upID)
+
+	scaleDownID := ""
+	if !scaledDown || repeatType != TurndownJobRepeatNone {
+		scaleDownID, err = ts.scheduler.Schedule(downTime, ts.scaleDown, scaleDownMeta)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	scaleUpID, err := ts.scheduler.Schedule(to, ts.scaleUp, scaleUpMeta)
+	if err != nil {
+		return nil, err
+	}
+
+	ts.schedule.ScaleDownID = scaleDownID
+	ts.schedule.ScaleDownTime = downTime
+	ts.schedule.ScaleDownMetadata = scaleDownMeta
+	ts.schedule.ScaleUpID = scaleUpID
+	ts.schedule.ScaleUpTime = to
+	ts.schedule.ScaleUpMetadata = scaleUpMeta
+	ts.schedule.SnoozedBy = ""
+	ts.schedule.SnoozeReason = ""
+	ts.store.Update(ts.schedule)
+
+	ts.log.Log("Schedule Updated: %+v", ts.schedule)
+
+	toReturn := *ts.schedule
+	return &toReturn, nil
+}
+
+// ScaleDownNow immediately scales down the cluster using the current schedule. The cluster is scaled back
+// up at the next scheduled scale up.
+func (ts *TurndownScheduler) ScaleDownNow() error {
+	return ts.TriggerScaleDown(ScaleDownReasonOnDemand)
+}
+
+// ScaleUpNow immediately scales up the cluster. For a one-shot schedule created by ScheduleScaleDownNow,
+// the scale up completes the schedule. Otherwise, the cluster stays up until the next scheduled scale
+// down.
+func (ts *TurndownScheduler) ScaleUpNow() error {
+	ts.lock.Lock()
+
+	if ts.schedule == nil {
+		ts.lock.Unlock()
+		return NoScheduleToTriggerErr
+	}
+
+	if !ts.schedule.ScaleUpTime.IsZero() {
+		ts.lock.Unlock()
+		return ts.TriggerScaleUp(ScaleUpReasonOnDemand, 0)
+	}
+
+	defer ts.lock.Unlock()
+
+	if ts.schedule.Current != TurndownJobTypeScaleUp || ts.scheduler.IsRunning(ts.schedule.ScaleDownID) || ts.isTriggerPending() {
+		return AlreadyScaledUpErr
+	}
+
+	ts.log.Log("Scaling Up On Demand Schedule")
+
+	now := time.Now()
+	scaleUpMeta := map[string]string{
+		TurndownJobType:   TurndownJobTypeScaleUp,
+		TurndownJobRepeat: TurndownJobRepeatNone,
+	}
+	scaleUpID, err := ts.scheduler.Schedule(now, ts.scaleUp, scaleUpMeta)
+	if err != nil {
+		return err
+	}
+
+	ts.schedule.ScaleUpID = scaleUpID
+	ts.schedule.ScaleUpTime = now
+	ts.schedule.ScaleUpMetadata = scaleUpMeta
+	ts.store.Update(ts.schedule)
+
+	return nil
+}
+
 // Cancels the turndown from occurring. The force bool should be used only if the job is
 // cancelled by a running child job.
 func (ts *TurndownScheduler) Cancel(force bool) error {
@@ -293,6 +614,167 @@ func (ts *TurndownScheduler) Cancel(force bool) error {
 	return nil
 }
 
+// TriggerScaleDown runs a scale down of the current schedule immediately, outside of the scheduled
+// scale down time. The cluster is scaled back up at the next scheduled scale up. The reason is recorded
+// on the schedule.
+func (ts *TurndownScheduler) TriggerScaleDown(reason string) error {
+	ts.lock.Lock()
+	defer ts.lock.Unlock()
+
+	if ts.schedule == nil {
+		return NoScheduleToTriggerErr
+	}
+
+	if ts.schedule.Current != TurndownJobTypeScaleDown || ts.scheduler.IsRunning(ts.schedule.ScaleDownID) || ts.isTriggerPending() {
+		return AlreadyScaledDownErr
+	}
+
+	ts.log.Log("Triggering Scale Down: %s", reason)
+
+	ts.schedule.ScaleDownReason = reason
+	ts.store.Update(ts.schedule)
+
+	id, err := ts.scheduler.Schedule(time.Now(), ts.scaleDown, triggeredScaleDownMetadata(ts.schedule.ScaleDownMetadata))
+	ts.triggeredID = id
+	return err
+}
+
+// TriggerScaleUp runs a scale up of the current schedule immediately, outside of the scheduled scale
+// up time. If stayUp is non-zero and ends before the next scheduled scale up, the cluster is scaled back
+// down once stayUp has elapsed. Otherwise, the cluster stays up until the next scheduled scale down.
+func (ts *TurndownScheduler) TriggerScaleUp(reason string, stayUp time.Duration) error {
+	ts.lock.Lock()
+	defer ts.lock.Unlock()
+
+	if ts.schedule == nil {
+		return NoScheduleToTriggerErr
+	}
+
+	if ts.schedule.Current != TurndownJobTypeScaleUp || ts.scheduler.IsRunning(ts.schedule.ScaleDownID) ||
+		ts.scheduler.IsRunning(ts.schedule.ScaleUpID) || ts.isTriggerPending() {
+		return AlreadyScaledUpErr
+	}
+
+	ts.log.Log("Triggering Scale Up: %s", reason)
+
+	now := time.Now()
+	ts.schedule.WakeUntil = time.Time{}
+	if stayUp > 0 && now.Add(stayUp).Before(ts.schedule.ScaleUpTime) {
+		ts.schedule.WakeUntil = now.Add(stayUp)
+	}
+	ts.store.Update(ts.schedule)
+
+	// A previous wake window no longer applies
+	if ts.wakeScaleDownID != "" {
+		ts.scheduler.Cancel(ts.wakeScaleDownID)
+		ts.wakeScaleDownID = ""
+	}
+
+	id, err := ts.scheduler.Schedule(now, ts.scaleUp, triggeredScaleUpMetadata())
+	ts.triggeredID = id
+	return err
+}
+
+// Snooze postpones the next scale down by the requested duration. The total postponement from the
+// originally scheduled time may not exceed max, and the scale down must remain before the next scale up.
+// Repeating schedules are rescheduled from the original time, so subsequent scale downs are unaffected.
+func (ts *TurndownScheduler) Snooze(duration time.Duration, max time.Duration, by string, reason string) (*Schedule, error) {
+	ts.lock.Lock()
+	defer ts.lock.Unlock()
+
+	if ts.schedule == nil {
+		return nil, NoScheduleToSnoozeErr
+	}
+
+	if ts.schedule.Current != TurndownJobTypeScaleDown {
+		return nil, AlreadyScaledDownErr
+	}
+
+	if ts.scheduler.IsRunning(ts.schedule.ScaleDownID) || ts.isTriggerPending() {
+		return nil, SnoozeWhileRunningErr
+	}
+
+	if duration <= 0 {
+		return nil, &RequestError{fmt.Errorf("The snooze duration must be positive.")}
+	}
+
+	metadata := make(map[string]string)
+	for k, v := range ts.schedule.ScaleDownMetadata {
+		metadata[k] = v
+	}
+
+	cadence := ts.schedule.ScaleDownTime
+	if c, ok := metadata[TurndownJobCadence]; ok {
+		if t, err := time.Parse(time.RFC3339Nano, c); err == nil {
+			cadence = t
+		}
+	}
+
+	until := ts.schedule.ScaleDownTime.Add(duration)
+	if until.Sub(cadence) > max {
+		return nil, &RequestError{fmt.Errorf("The scale down can be postponed by at most %s from %s.", max, cadence)}
+	}
+
+	// Keep the same minimum gap between scale down and scale up used to validate schedules
+	upTime := ts.schedule.ScaleUpTime
+	if upTime.After(ts.schedule.ScaleDownTime) && until.Add(20*time.Minute).After(upTime) {
+		return nil, &ConflictError{fmt.Errorf("The scale down must remain at least 20 mins before the next scale up (%s).", upTime)}
+	}
+
+	metadata[TurndownJobCadence] = cadence.Format(time.RFC3339Nano)
+
+	ts.scheduler.Cancel(ts.schedule.ScaleDownID)
+	id, err := ts.scheduler.Schedule(until, ts.scaleDown, metadata)
+	if err != nil {
+		return nil, err
+	}
+
+	ts.log.Log("Scale Down Snoozed until %s by %s: %s", until, by, reason)
+
+	ts.schedule.ScaleDownID = id
+	ts.schedule.ScaleDownTime = until
+	ts.schedule.ScaleDownMetadata = metadata
+	ts.schedule.SnoozedBy = by
+	ts.schedule.SnoozeReason = reason
+	ts.store.Update(ts.schedule)
+
+	toReturn := *ts.schedule
+	return &toReturn, nil
+}
+
+// Determines whether a triggered job is waiting to run or running. Assumes the lock is held.
+func (ts *TurndownScheduler) isTriggerPending() bool {
+	if ts.triggeredID == "" {
+		return false
+	}
+
+	_, ok := ts.scheduler.NextScheduledTimeFor(ts.triggeredID)
+	return ok || ts.scheduler.IsRunning(ts.triggeredID)
+}
+
+// Creates the metadata for a triggered scale down job, which runs once and is not rescheduled
+func triggeredScaleDownMetadata(downMeta map[string]string) map[string]string {
+	metadata := map[string]string{
+		TurndownJobType:      TurndownJobTypeScaleDown,
+		TurndownJobRepeat:    TurndownJobRepeatNone,
+		TurndownJobTriggered: "true",
+	}
+	if targets, ok := downMeta[TurndownJobTargets]; ok {
+		metadata[TurndownJobTargets] = targets
+	}
+
+	return metadata
+}
+
+// Creates the metadata for a triggered scale up job, which runs once and is not rescheduled
+func triggeredScaleUpMetadata() map[string]string {
+	return map[string]string{
+		TurndownJobType:      TurndownJobTypeScaleUp,
+		TurndownJobRepeat:    TurndownJobRepeatNone,
+		TurndownJobTriggered: "true",
+	}
+}
+
 func (ts *TurndownScheduler) GetSchedule() *Schedule {
 	ts.lock.Lock()
 	defer ts.lock.Unlock()
@@ -322,8 +804,9 @@ func (ts *TurndownScheduler) onJobCompleted(id string, scheduled time.Time, meta
 
 	// Handle Errors
 	if err != nil {
-		// Schedule is written, this is simply waiting on the pod to move nodes, so we just ignore any rescheduling
-		if err.Error() == "EnvironmentPrepare" || err.Error() == "Cancelled" {
+		// Schedule is written, this is simply waiting on the pod to move nodes, so we just ignore any rescheduling.
+		// Interrupted jobs are checkpointed, and resume once the schedule is restored.
+		if err.Error() == "EnvironmentPrepare" || err.Error() == "Cancelled" || err.Error() == "Interrupted" {
 			return
 		}
 
@@ -347,6 +830,11 @@ func (ts *TurndownScheduler) onJobCompleted(id string, scheduled time.Time, meta
 		}
 	}
 
+	if metadata[TurndownJobTriggered] == "true" {
+		ts.onTriggeredJobCompleted(jobType)
+		return
+	}
+
 	repeat, ok := metadata[TurndownJobRepeat]
 	if !ok || repeat == TurndownJobRepeatNone {
 		ts.log.Log("Did not find a repeat task. Not rescheduling")
@@ -356,16 +844,23 @@ func (ts *TurndownScheduler) onJobCompleted(id string, scheduled time.Time, meta
 		defer ts.lock.Unlock()
 
 		if jobType == TurndownJobTypeScaleUp {
+			// Persist the history of the final run before completing
+			ts.store.Update(ts.schedule)
 			ts.schedule = nil
 			ts.store.Complete()
 		} else if jobType == TurndownJobTypeScaleDown {
 			ts.schedule.Current = TurndownJobTypeScaleUp
+			ts.schedule.SnoozedBy = ""
+			ts.schedule.SnoozeReason = ""
 			ts.store.Update(ts.schedule)
 		}
 
 		return
 	}
 
+	// Postponed jobs repeat from their original time to keep the cadence
+	metadata, scheduled = withoutCadence(metadata, scheduled)
+
 	repeatDuration := repeatDurations[repeat]
 	newScheduled := scheduled.Add(repeatDuration)
 
@@ -390,8 +885,11 @@ func (ts *TurndownScheduler) onJobCompleted(id string, scheduled time.Time, meta
 		ts.schedule.ScaleDownID = newJobID
 		ts.schedule.ScaleDownTime = newScheduled
 		ts.schedule.ScaleDownMetadata = metadata
+		ts.schedule.SnoozedBy = ""
+		ts.schedule.SnoozeReason = ""
 	} else if jobType == TurndownJobTypeScaleUp {
 		ts.schedule.Current = TurndownJobTypeScaleDown
+		ts.schedule.ScaleDownReason = ""
 		ts.schedule.ScaleUpID = newJobID
 		ts.schedule.ScaleUpTime = newScheduled
 		ts.schedule.ScaleUpMetadata = metadata
@@ -400,9 +898,87 @@ func (ts *TurndownScheduler) onJobCompleted(id string, scheduled time.Time, meta
 	ts.store.Update(ts.schedule)
 }
 
+// Adds the original scheduled time to the metadata of a postponed job, unless the job was already postponed
+func withCadence(metadata map[string]string, scheduled time.Time) map[string]string {
+	result := make(map[string]string)
+	for k, v := range metadata {
+		result[k] = v
+	}
+
+	if _, ok := result[TurndownJobCadence]; !ok {
+		result[TurndownJobCadence] = scheduled.Format(time.RFC3339Nano)
+	}
+
+	return result
+}
+
+// Removes the cadence from the metadata of a postponed job, returning the original scheduled time
+func withoutCadence(metadata map[string]string, scheduled time.Time) (map[string]string, time.Time) {
+	cadence, ok := metadata[TurndownJobCadence]
+	if !ok {
+		return metadata, scheduled
+	}
+
+	result := make(map[string]string)
+	for k, v := range metadata {
+		if k != TurndownJobCadence {
+			result[k] = v
+		}
+	}
+
+	t, err := time.Parse(time.RFC3339Nano, cadence)
+	if err != nil {
+		return result, scheduled
+	}
+
+	return result, t
+}
+
+// Flips the current job type after a triggered job. The scheduled jobs remain in place, and skip
+// if the cluster is already in the expected state when they run.
+func (ts *TurndownScheduler) onTriggeredJobCompleted(jobType string) {
+	ts.lock.Lock()
+	defer ts.lock.Unlock()
+
+	if ts.schedule == nil {
+		return
+	}
+
+	if jobType == TurndownJobTypeScaleDown {
+		ts.schedule.Current = TurndownJobTypeScaleUp
+	} else if jobType == TurndownJobTypeScaleUp {
+		// The reason only applies to the completed cycle, so a failed scale up does not trigger another
+		// scale down once the schedule is restored
+		ts.schedule.Current = TurndownJobTypeScaleDown
+		ts.schedule.ScaleDownReason = ""
+
+		// Scale back down once the wake window elapses
+		if !ts.schedule.WakeUntil.IsZero() {
+			ts.schedule.ScaleDownReason = ScaleDownReasonWakeElapsed
+
+			id, err := ts.scheduler.Schedule(ts.schedule.WakeUntil, ts.scaleDown, triggeredScaleDownMetadata(ts.schedule.ScaleDownMetadata))
+			ts.wakeScaleDownID = id
+			if err != nil {
+				ts.log.Err("Failed to schedule scale down after wake window: %s", err.Error())
+			}
+		}
+	}
+
+	ts.store.Update(ts.schedule)
+}
+
 func (ts *TurndownScheduler) scaleDown() error {
 	klog.V(3).Info("-- Scale Down --")
 
+	// A triggered scale down already ran for the current cycle
+	if !ts.startScaleDown() {
+		ts.log.Log("Cluster was already Scaled Down. Skipping.")
+		return nil
+	}
+
+	ref := ts.store.Reference()
+	ts.manager.SetScheduleReference(ref)
+
 	// Determine if we are running on a single small node
 	isOnNode, err := ts.manager.IsRunningOnTurndownNode()
 	if nil != err {
@@ -426,6 +1002,7 @@ func (ts *TurndownScheduler) scaleDown() error {
 		}
 
 		ts.log.Log("Environment Preparation Completed. Pod will reschedule on target host node now.")
+		ts.checkpoint(TurndownJobTypeScaleDown)
 
 		// Since we'll be moving nodes and rescheduling, we'll return a "special" error here
 		return EnvironmentPrepareErr
@@ -433,16 +1010,262 @@ func (ts *TurndownScheduler) scaleDown() error {
 		ts.log.Log("Already running on correct turndown host node. No need to setup environment.")
 	}
 
-	return ts.manager.ScaleDownCluster()
+	targets := ts.nodePoolTargets()
+	run := ts.startRun(TurndownJobTypeScaleDown, ref, v1alpha1.TurndownRunPlan{NodePoolTargets: targets.Targets()})
+	err = ts.manager.ScaleDownCluster(targets)
+	ts.updateScaledDownPools()
+	ts.finishRun(TurndownJobTypeScaleDown, run, ts.manager.ScaledDownNodePools(), err)
+
+	if err == InterruptedErr {
+		ts.checkpoint(TurndownJobTypeScaleDown)
+		ts.recordEvent(ref, corev1.EventTypeNormal, ScaleDownInterrupted, "Scale down interrupted by shutdown. It will resume on the next pod.")
+		return err
+	}
+	ts.clearCheckpoint()
+
+	if err != nil {
+		ts.recordEvent(ref, corev1.EventTypeWarning, ScaleDownFailed, "Failed to scale down cluster: %s", err.Error())
+	} else {
+		ts.recordEvent(ref, corev1.EventTypeNormal, ScaleDownComplete, "Scaled down cluster: %s", ts.scaleDownReason())
+	}
+
+	return err
+}
+
+// The reason recorded for the current scale down
+func (ts *TurndownScheduler) scaleDownReason() string {
+	ts.lock.Lock()
+	defer ts.lock.Unlock()
+
+	if ts.schedule == nil {
+		return ScaleDownReasonScheduled
+	}
+
+	return ts.schedule.ScaleDownReason
+}
+
+// Loads the node pool targets from the scale down metadata of the current schedule
+func (ts *TurndownScheduler) nodePoolTargets() NodePoolTargets {
+	ts.lock.Lock()
+	defer ts.lock.Unlock()
+
+	if ts.schedule == nil {
+		return nil
+	}
+
+	targets, err := ParseNodePoolTargets(ts.schedule.ScaleDownMetadata[TurndownJobTargets])
+	if err != nil {
+		ts.log.Err("Failed to parse node pool targets, scaling down to 0: %s", err.Error())
+		return nil
+	}
+
+	return targets
 }
 
 func (ts *TurndownScheduler) scaleUp() error {
 	klog.V(3).Info("-- Scale Up --")
+
+	// A triggered scale up already ran for the current cycle
+	if !ts.startScaleUp() {
+		ts.log.Log("Cluster was already Scaled Up. Skipping.")
+		return nil
+	}
+
+	ref := ts.store.Reference()
+	ts.manager.SetScheduleReference(ref)
+
+	pools := ts.manager.ScaledDownNodePools()
+	run := ts.startRun(TurndownJobTypeScaleUp, ref, v1alpha1.TurndownRunPlan{NodePools: pools})
 	err := ts.manager.ScaleUpCluster()
+	ts.updateScaledDownPools()
+	ts.finishRun(TurndownJobTypeScaleUp, run, pools, err)
+
+	if err == InterruptedErr {
+		ts.checkpoint(TurndownJobTypeScaleUp)
+		ts.recordEvent(ref, corev1.EventTypeNormal, ScaleUpInterrupted, "Scale up interrupted by shutdown. It will resume on the next pod.")
+		return err
+	}
+	ts.clearCheckpoint()
+
+	if err != nil {
+		ts.recordEvent(ref, corev1.EventTypeWarning, ScaleUpFailed, "Failed to scale up cluster: %s", err.Error())
+	} else {
+		ts.recordEvent(ref, corev1.EventTypeNormal, ScaleUpComplete, "Scaled up cluster")
+	}
 
 	return err
 }
 
+// Determines whether the scale down should run, and records the scheduled reason if the scale down
+// was not triggered
+func (ts *TurndownScheduler) startScaleDown() bool {
+	ts.lock.Lock()
+	defer ts.lock.Unlock()
+
+	if ts.schedule == nil {
+		return true
+	}
+
+	if ts.schedule.Current == TurndownJobTypeScaleUp {
+		return false
+	}
+
+	if ts.schedule.ScaleDownReason == "" {
+		ts.schedule.ScaleDownReason = ScaleDownReasonScheduled
+	}
+	ts.schedule.WakeUntil = time.Time{}
+
+	return true
+}
+
+// Determines whether the scale up should run
+func (ts *TurndownScheduler) startScaleUp() bool {
+	ts.lock.Lock()
+	defer ts.lock.Unlock()
+
+	return ts.schedule == nil || ts.schedule.Current != TurndownJobTypeScaleDown
+}
+
+// Records the node pools currently scaled down by the manager on the schedule. These are persisted
+// to the store once the job completes.
+func (ts *TurndownScheduler) updateScaledDownPools() {
+	ts.lock.Lock()
+	defer ts.lock.Unlock()
+
+	if ts.schedule == nil {
+		return
+	}
+
+	ts.schedule.ScaledDownPools = ts.manager.ScaledDownNodePools()
+}
+
+// Records an event on the TurndownSchedule, if the store persists the schedule as a resource
+func (ts *TurndownScheduler) recordEvent(ref *corev1.ObjectReference, eventType string, reason string, messageFmt string, args ...interface{}) {
+	if ref == nil {
+		return
+	}
+
+	ts.recorder.Eventf(ref, eventType, reason, messageFmt, args...)
+}
+
+// A scale down or scale up in progress, along with the name of the run recording it
+type runRecord struct {
+	name   string
+	start  time.Time
+	report *RunReport
+}
+
+// Marks the job as running on the schedule and persists it, so the status reflects the run in progress.
+// The run is recorded in the run store, and the manager reports the result of each step on it.
+func (ts *TurndownScheduler) startRun(jobType string, ref *corev1.ObjectReference, plan v1alpha1.TurndownRunPlan) *runRecord {
+	run := &runRecord{
+		start:  time.Now().UTC(),
+		report: NewRunReport(),
+	}
+	ts.manager.SetRunReport(run.report)
+
+	spec := v1alpha1.TurndownRunSpec{
+		Type: jobType,
+		Plan: plan,
+	}
+	if ref != nil {
+		spec.Schedule = ref.Name
+	}
+
+	// Steps restored from an interrupted run are skipped by the manager
+	spec.Plan.ResumedSteps = ts.manager.CompletedSteps()
+
+	ts.lock.Lock()
+	if ts.schedule != nil {
+		if jobType == TurndownJobTypeScaleDown {
+			spec.Reason = ts.schedule.ScaleDownReason
+		}
+
+		ts.schedule.Running = jobType
+		err := ts.store.Update(ts.schedule)
+		if err != nil {
+			ts.log.Err("Failed to update schedule status for running job: %s", err.Error())
+		}
+	}
+	ts.lock.Unlock()
+
+	name, err := ts.runs.Create(ref, spec, run.start)
+	if err != nil {
+		ts.log.Err("Failed to record turndown run: %s", err.Error())
+	}
+	run.name = name
+
+	return run
+}
+
+// Records the outcome of the run on the schedule and in the run store. The schedule is persisted to the
+// store once the job completes or is checkpointed.
+func (ts *TurndownScheduler) finishRun(jobType string, run *runRecord, pools []string, err error) {
+	ts.manager.SetRunReport(nil)
+	result := newScheduleRun(jobType, run.start, pools, err)
+
+	if run.name != "" {
+		completeErr := ts.runs.Complete(run.name, result, run.report)
+		if completeErr != nil {
+			ts.log.Err("Failed to record outcome of turndown run: %s - %s", run.name, completeErr.Error())
+		}
+	}
+
+	ts.lock.Lock()
+	defer ts.lock.Unlock()
+
+	if ts.schedule == nil {
+		return
+	}
+
+	ts.schedule.Running = ""
+	ts.schedule.LastRun = result
+}
+
+// Saves the steps completed by an interrupted scale down or scale up to the store, so the job resumes
+// once the schedule is restored by the next pod
+func (ts *TurndownScheduler) checkpoint(jobType string) {
+	ts.lock.Lock()
+	defer ts.lock.Unlock()
+
+	if ts.schedule == nil {
+		return
+	}
+
+	ts.schedule.InterruptedJob = jobType
+	ts.schedule.CompletedSteps = ts.manager.CompletedSteps()
+
+	ts.log.Log("Checkpointing Interrupted Job: %s - Completed Steps: %s", jobType, strings.Join(ts.schedule.CompletedSteps, ", "))
+	err := ts.store.Update(ts.schedule)
+	if err != nil {
+		ts.log.Err("Failed to checkpoint interrupted job: %s", err.Error())
+	}
+}
+
+// Clears the checkpoint after a scale down or scale up runs to completion. The schedule is persisted to
+// the store once the job completes.
+func (ts *TurndownScheduler) clearCheckpoint() {
+	ts.manager.ResumeSteps(nil)
+
+	ts.lock.Lock()
+	defer ts.lock.Unlock()
+
+	if ts.schedule == nil {
+		return
+	}
+
+	ts.schedule.InterruptedJob = ""
+	ts.schedule.CompletedSteps = nil
+}
+
+// Shutdown stops scheduled jobs from starting, and waits for a running job to complete or checkpoint.
+// Scheduled jobs remain in the store for the next pod.
+func (ts *TurndownScheduler) Shutdown() {
+	ts.log.Log("Shutting down. Waiting for running jobs...")
+	ts.scheduler.Stop()
+	ts.log.Log("Shutdown complete.")
+}
+
 func (ts *TurndownScheduler) reset() error {
 	klog.V(3).Info("-- Reset --")
 	err := ts.manager.ResetTurndownEnvironment()
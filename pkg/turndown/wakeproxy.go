@@ -0,0 +1,299 @@
+package turndown
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"reflect"
+	"time"
+
+	"github.com/kubecost/cluster-turndown/pkg/logging"
+	"github.com/kubecost/cluster-turndown/pkg/turndown/patcher"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/kubernetes"
+)
+
+const (
+	// Label on services redirected to the wake proxy, and the annotation storing the original selector
+	KubecostTurnDownRedirected = "kubecost.kubernetes.io/turn-down-redirected"
+	KubecostTurnDownSelector   = "kubecost.kubernetes.io/turn-down-selector"
+
+	WakeProxyPort = 9732
+
+	// Redirected services are restored after this timeout, even if their workloads are not ready
+	WakeRestoreTimeout = 15 * time.Minute
+)
+
+const wakingPage = `<!DOCTYPE html>
+<html>
+<head>
+  <meta charset="utf-8">
+  <meta http-equiv="refresh" content="15">
+  <title>Cluster is waking up</title>
+</head>
+<body style="font-family: sans-serif; text-align: center; margin-top: 20%;">
+  <h1>The cluster is waking up</h1>
+  <p>This cluster was turned down on a schedule and is being scaled back up. This page will refresh
+  automatically once your workloads are ready.</p>
+</body>
+</html>
+`
+
+// WakeProxy redirects the services behind ingresses in opted-in namespaces to the turndown pod while the
+// cluster is scaled down, and serves a page which wakes the cluster for any request it receives.
+type WakeProxy struct {
+	client kubernetes.Interface
+	podIP  string
+	wake   func(reason string)
+	log    logging.NamedLogger
+}
+
+// Creates a new WakeProxy instance, calling wake on each request received
+func NewWakeProxy(client kubernetes.Interface, wake func(reason string)) *WakeProxy {
+	return &WakeProxy{
+		client: client,
+		podIP:  os.Getenv("POD_IP"),
+		wake:   wake,
+		log:    logging.NamedLogger("WakeProxy"),
+	}
+}
+
+// ServeHTTP wakes the cluster and serves the waking page for every request
+func (wp *WakeProxy) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	wp.wake(fmt.Sprintf("HTTP request for %s%s", r.Host, r.URL.Path))
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.Header().Set("Cache-Control", "no-store")
+	w.Header().Set("Retry-After", "15")
+	w.WriteHeader(http.StatusServiceUnavailable)
+	w.Write([]byte(wakingPage))
+}
+
+// Redirect points every service backing an ingress in an opted-in namespace at the wake proxy. The
+// original selector is stored on the service, and the endpoints are set to the turndown pod.
+func (wp *WakeProxy) Redirect() error {
+	if wp.podIP == "" {
+		return fmt.Errorf("POD_IP is not set. Cannot redirect services to the wake proxy.")
+	}
+
+	namespaces, err := wp.client.CoreV1().Namespaces().List(metav1.ListOptions{
+		LabelSelector: KubecostTurnDownWakeLabel + "=true",
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, ns := range namespaces.Items {
+		for _, name := range wp.ingressServices(ns.Name) {
+			service, err := wp.client.CoreV1().Services(ns.Name).Get(name, metav1.GetOptions{})
+			if err != nil {
+				wp.log.Warn("Failed to get service %s/%s: %s", ns.Name, name, err.Error())
+				continue
+			}
+
+			err = wp.redirectService(service)
+			if err != nil {
+				wp.log.Err("Failed to redirect service %s/%s: %s", ns.Name, name, err.Error())
+			}
+		}
+	}
+
+	return nil
+}
+
+// Restore returns redirected services to their original selectors once a pod matching the selector
+// is ready. If force is set, services are restored regardless of pod readiness.
+func (wp *WakeProxy) Restore(force bool) error {
+	services, err := wp.client.CoreV1().Services(metav1.NamespaceAll).List(metav1.ListOptions{
+		LabelSelector: KubecostTurnDownRedirected + "=true",
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, service := range services.Items {
+		selector := make(map[string]string)
+		err := json.Unmarshal([]byte(service.Annotations[KubecostTurnDownSelector]), &selector)
+		if err != nil {
+			wp.log.Err("Failed to parse original selector for service %s/%s: %s", service.Namespace, service.Name, err.Error())
+			continue
+		}
+
+		if !force && !wp.hasReadyPod(service.Namespace, selector) {
+			continue
+		}
+
+		_, err = patcher.PatchService(wp.client, service, func(s *v1.Service) error {
+			s.Spec.Selector = selector
+			delete(s.Labels, KubecostTurnDownRedirected)
+			delete(s.Annotations, KubecostTurnDownSelector)
+			return nil
+		})
+		if err != nil {
+			wp.log.Err("Failed to restore service %s/%s: %s", service.Namespace, service.Name, err.Error())
+			continue
+		}
+
+		wp.log.Log("Restored service %s/%s", service.Namespace, service.Name)
+	}
+
+	return nil
+}
+
+// Finds the names of the services used as backends by the ingresses in a namespace. Ingresses are listed
+// from both the extensions and networking.k8s.io API groups, as clusters may only serve one of them.
+func (wp *WakeProxy) ingressServices(namespace string) []string {
+	seen := make(map[string]bool)
+	names := []string{}
+	add := func(name string) {
+		if name != "" && !seen[name] {
+			seen[name] = true
+			names = append(names, name)
+		}
+	}
+
+	extIngresses, extErr := wp.client.ExtensionsV1beta1().Ingresses(namespace).List(metav1.ListOptions{})
+	if extErr == nil {
+		for _, ingress := range extIngresses.Items {
+			if ingress.Spec.Backend != nil {
+				add(ingress.Spec.Backend.ServiceName)
+			}
+
+			for _, rule := range ingress.Spec.Rules {
+				if rule.HTTP == nil {
+					continue
+				}
+
+				for _, path := range rule.HTTP.Paths {
+					add(path.Backend.ServiceName)
+				}
+			}
+		}
+	}
+
+	netIngresses, netErr := wp.client.NetworkingV1beta1().Ingresses(namespace).List(metav1.ListOptions{})
+	if netErr == nil {
+		for _, ingress := range netIngresses.Items {
+			if ingress.Spec.Backend != nil {
+				add(ingress.Spec.Backend.ServiceName)
+			}
+
+			for _, rule := range ingress.Spec.Rules {
+				if rule.HTTP == nil {
+					continue
+				}
+
+				for _, path := range rule.HTTP.Paths {
+					add(path.Backend.ServiceName)
+				}
+			}
+		}
+	}
+
+	if extErr != nil && netErr != nil {
+		wp.log.Warn("Failed to list ingresses in %s: %s, %s", namespace, extErr.Error(), netErr.Error())
+	}
+
+	return names
+}
+
+// Removes the selector from the service, storing the original, and points its endpoints at the
+// turndown pod. Services without a selector that were not redirected by turndown are skipped.
+func (wp *WakeProxy) redirectService(service *v1.Service) error {
+	redirected := service.Labels[KubecostTurnDownRedirected] == "true"
+	if !redirected {
+		if len(service.Spec.Selector) == 0 || service.Spec.Type == v1.ServiceTypeExternalName {
+			return nil
+		}
+
+		selector, err := json.Marshal(service.Spec.Selector)
+		if err != nil {
+			return err
+		}
+
+		_, err = patcher.PatchService(wp.client, *service, func(s *v1.Service) error {
+			if s.Labels == nil {
+				s.Labels = make(map[string]string)
+			}
+			if s.Annotations == nil {
+				s.Annotations = make(map[string]string)
+			}
+
+			s.Labels[KubecostTurnDownRedirected] = "true"
+			s.Annotations[KubecostTurnDownSelector] = string(selector)
+			s.Spec.Selector = nil
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+
+		wp.log.Log("Redirected service %s/%s to the wake proxy", service.Namespace, service.Name)
+	}
+
+	ports := []v1.EndpointPort{}
+	for _, port := range service.Spec.Ports {
+		ports = append(ports, v1.EndpointPort{
+			Name:     port.Name,
+			Port:     WakeProxyPort,
+			Protocol: v1.ProtocolTCP,
+		})
+	}
+
+	subsets := []v1.EndpointSubset{
+		{
+			Addresses: []v1.EndpointAddress{{IP: wp.podIP}},
+			Ports:     ports,
+		},
+	}
+
+	endpoints, err := wp.client.CoreV1().Endpoints(service.Namespace).Get(service.Name, metav1.GetOptions{})
+	if errors.IsNotFound(err) {
+		_, err = wp.client.CoreV1().Endpoints(service.Namespace).Create(&v1.Endpoints{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      service.Name,
+				Namespace: service.Namespace,
+			},
+			Subsets: subsets,
+		})
+		return err
+	}
+	if err != nil {
+		return err
+	}
+
+	// The turndown pod address only changes if it is rescheduled
+	if reflect.DeepEqual(endpoints.Subsets, subsets) {
+		return nil
+	}
+
+	endpoints.Subsets = subsets
+	_, err = wp.client.CoreV1().Endpoints(service.Namespace).Update(endpoints)
+	return err
+}
+
+// Determines whether any pod matching the selector is ready
+func (wp *WakeProxy) hasReadyPod(namespace string, selector map[string]string) bool {
+	pods, err := wp.client.CoreV1().Pods(namespace).List(metav1.ListOptions{
+		LabelSelector: labels.SelectorFromSet(selector).String(),
+	})
+	if err != nil {
+		wp.log.Warn("Failed to list pods in %s: %s", namespace, err.Error())
+		return false
+	}
+
+	for _, pod := range pods.Items {
+		for _, condition := range pod.Status.Conditions {
+			if condition.Type == v1.PodReady && condition.Status == v1.ConditionTrue {
+				return true
+			}
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,236 @@
+package turndown
+
+import (
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/kubecost/cluster-turndown/pkg/apis/turndownschedule/v1alpha1"
+	clientset "github.com/kubecost/cluster-turndown/pkg/generated/clientset/versioned"
+	"github.com/kubecost/cluster-turndown/pkg/logging"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/kubernetes"
+)
+
+const (
+	// Namespaces labeled with this key and a "true" value wake the cluster on pending pods
+	KubecostTurnDownWakeLabel = "kubecost.kubernetes.io/turndown-wake"
+
+	WakeWatcherInterval = 30 * time.Second
+)
+
+// WakeWatcher triggers a scale up on the current schedule when new pods become unschedulable in opted-in
+// namespaces while the cluster is scaled down. If the policy enables HTTP, ingress backends in opted-in
+// namespaces are redirected to the wake proxy while the cluster is scaled down.
+type WakeWatcher struct {
+	client    kubernetes.Interface
+	tdClient  clientset.Interface
+	scheduler *TurndownScheduler
+	proxy     *WakeProxy
+	policy    *v1alpha1.WakePolicy
+	lock      *sync.Mutex
+	downSince time.Time
+	upSince   time.Time
+	log       logging.NamedLogger
+}
+
+// Creates a new WakeWatcher instance
+func NewWakeWatcher(client kubernetes.Interface, tdClient clientset.Interface, scheduler *TurndownScheduler) *WakeWatcher {
+	ww := &WakeWatcher{
+		client:    client,
+		tdClient:  tdClient,
+		scheduler: scheduler,
+		lock:      new(sync.Mutex),
+		log:       logging.NamedLogger("WakeWatcher"),
+	}
+
+	ww.proxy = NewWakeProxy(client, ww.wakeFromRequest)
+	return ww
+}
+
+// Proxy returns the handler serving the waking page for redirected ingress backends
+func (ww *WakeWatcher) Proxy() http.Handler {
+	return ww.proxy
+}
+
+// Run checks for pending pods on an interval until the stop channel is closed.
+func (ww *WakeWatcher) Run(stopCh <-chan struct{}) {
+	go wait.Until(ww.check, WakeWatcherInterval, stopCh)
+}
+
+func (ww *WakeWatcher) check() {
+	policy := ww.wakePolicy()
+	schedule := ww.scheduler.GetSchedule()
+
+	ww.lock.Lock()
+	ww.policy = policy
+	ww.lock.Unlock()
+
+	scaledDown := policy != nil && schedule != nil && schedule.Current == TurndownJobTypeScaleUp
+	ww.updateProxy(policy, scaledDown)
+
+	// Only watch while there is a policy and the cluster is scaled down
+	if !scaledDown {
+		ww.downSince = time.Time{}
+		return
+	}
+
+	// Only workloads created after the cluster was observed scaled down wake the cluster. Pods
+	// recreated for existing workloads by the scale down are not considered.
+	if ww.downSince.IsZero() {
+		ww.downSince = time.Now()
+		return
+	}
+
+	pod, err := ww.findWakingPod()
+	if err != nil {
+		ww.log.Warn("Failed to check for pending pods: %s", err.Error())
+		return
+	}
+
+	if pod == nil {
+		return
+	}
+
+	reason := fmt.Sprintf("Pod %s/%s is pending", pod.Namespace, pod.Name)
+	err = ww.scheduler.TriggerScaleUp(reason, policy.StayUp.Duration)
+	if err != nil {
+		ww.log.Err("Failed to trigger scale up: %s", err.Error())
+		return
+	}
+
+	ww.downSince = time.Time{}
+}
+
+// Redirects ingress backends to the wake proxy while the cluster is scaled down, and restores them
+// once the cluster is back up and their workloads are ready
+func (ww *WakeWatcher) updateProxy(policy *v1alpha1.WakePolicy, scaledDown bool) {
+	if scaledDown && policy.HTTP {
+		ww.upSince = time.Time{}
+
+		err := ww.proxy.Redirect()
+		if err != nil {
+			ww.log.Err("Failed to redirect ingress backends: %s", err.Error())
+		}
+		return
+	}
+
+	if ww.upSince.IsZero() {
+		ww.upSince = time.Now()
+	}
+
+	err := ww.proxy.Restore(time.Since(ww.upSince) > WakeRestoreTimeout)
+	if err != nil {
+		ww.log.Err("Failed to restore ingress backends: %s", err.Error())
+	}
+}
+
+// Triggers a scale up for a request received by the wake proxy
+func (ww *WakeWatcher) wakeFromRequest(reason string) {
+	ww.lock.Lock()
+	policy := ww.policy
+	ww.lock.Unlock()
+
+	if policy == nil || !policy.HTTP {
+		return
+	}
+
+	err := ww.scheduler.TriggerScaleUp(reason, policy.StayUp.Duration)
+	if err != nil && err != AlreadyScaledUpErr {
+		ww.log.Err("Failed to trigger scale up: %s", err.Error())
+	}
+}
+
+// Locates the wake policy on the active TurndownSchedule resource
+func (ww *WakeWatcher) wakePolicy() *v1alpha1.WakePolicy {
+	tds, err := ww.tdClient.KubecostV1alpha1().TurndownSchedules().List(metav1.ListOptions{})
+	if err != nil {
+		ww.log.Warn("Failed to list TurndownSchedules: %s", err.Error())
+		return nil
+	}
+
+	for _, td := range tds.Items {
+		if td.Status.State == ScheduleStateSuccess {
+			return td.Spec.WakePolicy
+		}
+	}
+
+	return nil
+}
+
+// Finds the first unschedulable pod in an opted-in namespace that belongs to a new workload
+func (ww *WakeWatcher) findWakingPod() (*v1.Pod, error) {
+	namespaces, err := ww.client.CoreV1().Namespaces().List(metav1.ListOptions{
+		LabelSelector: KubecostTurnDownWakeLabel + "=true",
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	for _, ns := range namespaces.Items {
+		pods, err := ww.client.CoreV1().Pods(ns.Name).List(metav1.ListOptions{
+			FieldSelector: "status.phase=Pending",
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		for i := range pods.Items {
+			pod := &pods.Items[i]
+			if isUnschedulable(pod) && ww.isNewWorkload(pod) {
+				return pod, nil
+			}
+		}
+	}
+
+	return nil, nil
+}
+
+// Determines whether the pod, or the workload controlling it, was created after the cluster was
+// observed scaled down. DaemonSet pods never wake the cluster.
+func (ww *WakeWatcher) isNewWorkload(pod *v1.Pod) bool {
+	created := pod.CreationTimestamp
+
+	owner := metav1.GetControllerOf(pod)
+	if owner != nil {
+		var err error
+		var meta metav1.Object
+
+		switch owner.Kind {
+		case "DaemonSet":
+			return false
+		case "ReplicaSet":
+			meta, err = ww.client.AppsV1().ReplicaSets(pod.Namespace).Get(owner.Name, metav1.GetOptions{})
+		case "StatefulSet":
+			meta, err = ww.client.AppsV1().StatefulSets(pod.Namespace).Get(owner.Name, metav1.GetOptions{})
+		case "Job":
+			meta, err = ww.client.BatchV1().Jobs(pod.Namespace).Get(owner.Name, metav1.GetOptions{})
+		}
+
+		if err != nil {
+			ww.log.Warn("Failed to get %s %s/%s: %s", owner.Kind, pod.Namespace, owner.Name, err.Error())
+			return false
+		}
+
+		if meta != nil {
+			created = meta.GetCreationTimestamp()
+		}
+	}
+
+	return created.Time.After(ww.downSince)
+}
+
+// Determines whether the scheduler has marked the pod as unschedulable
+func isUnschedulable(pod *v1.Pod) bool {
+	for _, condition := range pod.Status.Conditions {
+		if condition.Type == v1.PodScheduled {
+			return condition.Status == v1.ConditionFalse && condition.Reason == v1.PodReasonUnschedulable
+		}
+	}
+
+	return false
+}
@@ -48,7 +48,11 @@ stage: "ALPHA"
 includedPermissions:
 - container.clusters.get
 - container.clusters.update
+- compute.instanceGroupManagers.get
+- compute.instanceGroupManagers.update
+- compute.instances.delete
 - compute.instances.list
+- compute.zoneOperations.get
 - iam.serviceAccounts.actAs
 - container.nodes.create
 - container.nodes.delete